@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"avito-shop/config"
 	"avito-shop/internal/app"
@@ -13,5 +14,25 @@ func main() {
 		log.Fatalf("Config error: %s", err)
 	}
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "recompute-balances":
+			app.RecomputeBalances(cfg)
+			return
+		case "reconcile":
+			app.Reconcile(cfg)
+			return
+		case "create-admin":
+			if len(os.Args) < 3 {
+				log.Fatalf("Usage: %s create-admin <username>", os.Args[0])
+			}
+
+			app.CreateAdmin(cfg, os.Args[2])
+			return
+		default:
+			log.Fatalf("Unknown command: %s", os.Args[1])
+		}
+	}
+
 	app.Run(cfg)
 }
@@ -2,16 +2,30 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type (
 	Config struct {
-		Env  string `yaml:"env"`
-		App  `yaml:"app"`
-		HTTP `yaml:"http"`
-		PG   `yaml:"postgres"`
+		Env            string `yaml:"env"`
+		App            `yaml:"app"`
+		HTTP           `yaml:"http"`
+		JWT            `yaml:"jwt"`
+		PG             `yaml:"postgres"`
+		Workers        `yaml:"workers"`
+		Scheduler      `yaml:"scheduler"`
+		CoinRequest    `yaml:"coin_request"`
+		Reconciliation `yaml:"reconciliation"`
+		Onboarding     `yaml:"onboarding"`
+		CoinExpiry     `yaml:"coin_expiry"`
+		SendPolicy     `yaml:"send_policy"`
+		Leaderboard    `yaml:"leaderboard"`
+		Stats          `yaml:"stats"`
+		Tracing        `yaml:"tracing"`
+		Health         `yaml:"health"`
+		Shutdown       `yaml:"shutdown"`
 	}
 
 	App struct {
@@ -21,12 +35,96 @@ type (
 
 	HTTP struct {
 		Port string `env-required:"true" yaml:"port" env:"HTTP_PORT"`
+		// InternalPort serves /metrics; keep it off the public network.
+		InternalPort string `env-default:"9090" yaml:"internal_port" env:"HTTP_INTERNAL_PORT"`
+	}
+
+	// JWT signs the auth tokens, which carry the admin role. There is no
+	// default on purpose: set JWT_SECRET to a random value of 32+ bytes.
+	JWT struct {
+		Secret string `env-required:"true" yaml:"secret" env:"JWT_SECRET"`
 	}
 
 	PG struct {
 		PoolMax int    `env-required:"true" yaml:"pool_max" env:"PG_POOL_MAX"`
 		URL     string `env-required:"true" yaml:"url"      env:"PG_URL"`
 	}
+
+	// Workers sizes the worker pools the HTTP routes run on.
+	Workers struct {
+		EnqueueTimeout time.Duration `env-default:"50ms" yaml:"enqueue_timeout" env:"WORKERS_ENQUEUE_TIMEOUT"`
+		Auth           WorkerPool    `yaml:"auth"    env-prefix:"WORKERS_AUTH_"`
+		Writes         WorkerPool    `yaml:"writes"  env-prefix:"WORKERS_WRITES_"`
+		Reads          WorkerPool    `yaml:"reads"   env-prefix:"WORKERS_READS_"`
+		Exports        WorkerPool    `yaml:"exports" env-prefix:"WORKERS_EXPORTS_"`
+	}
+
+	WorkerPool struct {
+		Workers int `env-required:"true" yaml:"workers" env:"WORKERS"`
+		Queue   int `env-required:"true" yaml:"queue"   env:"QUEUE"`
+	}
+
+	Scheduler struct {
+		Interval time.Duration `env-default:"1m" yaml:"interval" env:"SCHEDULER_INTERVAL"`
+	}
+
+	CoinRequest struct {
+		TTL time.Duration `env-default:"168h" yaml:"ttl" env:"COIN_REQUEST_TTL"`
+	}
+
+	Reconciliation struct {
+		Interval time.Duration `env-default:"1h" yaml:"interval" env:"RECONCILIATION_INTERVAL"`
+	}
+
+	Onboarding struct {
+		Grant int `env-default:"1000" yaml:"grant" env:"ONBOARDING_GRANT"`
+	}
+
+	CoinExpiry struct {
+		TTL      time.Duration `env-default:"8760h" yaml:"ttl"      env:"COIN_EXPIRY_TTL"`
+		Interval time.Duration `env-default:"1h"    yaml:"interval" env:"COIN_EXPIRY_INTERVAL"`
+	}
+
+	// SendPolicy limits outgoing transfers per user; zero disables a rule.
+	SendPolicy struct {
+		DailyLimit        int `env-default:"0" yaml:"daily_limit"        env:"SEND_DAILY_LIMIT"`
+		MonthlyLimit      int `env-default:"0" yaml:"monthly_limit"      env:"SEND_MONTHLY_LIMIT"`
+		ApprovalThreshold int `env-default:"0" yaml:"approval_threshold" env:"SEND_APPROVAL_THRESHOLD"`
+	}
+
+	Leaderboard struct {
+		Size            int           `env-default:"10"  yaml:"size"             env:"LEADERBOARD_SIZE"`
+		RefreshInterval time.Duration `env-default:"10m" yaml:"refresh_interval" env:"LEADERBOARD_REFRESH_INTERVAL"`
+	}
+
+	Stats struct {
+		RollupInterval time.Duration `env-default:"1h" yaml:"rollup_interval" env:"STATS_ROLLUP_INTERVAL"`
+	}
+
+	// Tracing exports spans over OTLP/HTTP ("otlp"), to stdout ("stdout") or
+	// nowhere ("none"). An empty endpoint defers to OTEL_EXPORTER_OTLP_*.
+	Tracing struct {
+		Exporter    string  `env-default:"none" yaml:"exporter"     env:"TRACING_EXPORTER"`
+		Endpoint    string  `yaml:"endpoint"     env:"TRACING_ENDPOINT"`
+		SampleRatio float64 `env-default:"1"    yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+	}
+
+	// Health configures the probes. DrainDelay is how long /readyz fails
+	// before the HTTP server stops, so the load balancer stops routing first.
+	Health struct {
+		MigrationsDir string        `env-default:"migrations" yaml:"migrations_dir" env:"HEALTH_MIGRATIONS_DIR"`
+		DrainDelay    time.Duration `env-default:"5s"         yaml:"drain_delay"    env:"HEALTH_DRAIN_DELAY"`
+	}
+
+	// Shutdown bounds each stage of the graceful shutdown; they run in the
+	// order of the fields, after the readiness drain.
+	Shutdown struct {
+		HTTP     time.Duration `env-default:"10s" yaml:"http"     env:"SHUTDOWN_HTTP_TIMEOUT"`
+		Workers  time.Duration `env-default:"10s" yaml:"workers"  env:"SHUTDOWN_WORKERS_TIMEOUT"`
+		Jobs     time.Duration `env-default:"30s" yaml:"jobs"     env:"SHUTDOWN_JOBS_TIMEOUT"`
+		Postgres time.Duration `env-default:"5s"  yaml:"postgres" env:"SHUTDOWN_POSTGRES_TIMEOUT"`
+		Tracing  time.Duration `env-default:"5s"  yaml:"tracing"  env:"SHUTDOWN_TRACING_TIMEOUT"`
+	}
 )
 
 func NewConfig() (*Config, error) {
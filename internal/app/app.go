@@ -1,31 +1,56 @@
 package app
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"net/http"
 	"os"
 	"os/signal"
 	_ "sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
-	_ "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
-	_ "github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/exp/slog"
 
 	"avito-shop/config"
 	"avito-shop/internal/controller"
+	"avito-shop/internal/controller/aggregator"
+	"avito-shop/internal/controller/expirer"
+	"avito-shop/internal/controller/reconciler"
+	"avito-shop/internal/controller/refresher"
+	"avito-shop/internal/controller/scheduler"
 	"avito-shop/internal/controller/worker"
-	_ "avito-shop/internal/repository"
+	"avito-shop/internal/entity"
+	repo "avito-shop/internal/repository"
+	"avito-shop/internal/usecase/analytics"
+	"avito-shop/internal/usecase/auth"
+	"avito-shop/internal/usecase/buy"
+	"avito-shop/internal/usecase/coinrequest"
+	"avito-shop/internal/usecase/expiry"
+	"avito-shop/internal/usecase/grant"
+	"avito-shop/internal/usecase/health"
+	"avito-shop/internal/usecase/history"
+	"avito-shop/internal/usecase/idempotency"
+	"avito-shop/internal/usecase/info"
+	"avito-shop/internal/usecase/reconcile"
+	"avito-shop/internal/usecase/reversal"
+	"avito-shop/internal/usecase/schedule"
+	"avito-shop/internal/usecase/send"
+	"avito-shop/internal/usecase/statement"
+	"avito-shop/internal/usecase/stats"
 	"avito-shop/pkg/httpserver"
+	"avito-shop/pkg/jwt"
+	"avito-shop/pkg/lifecycle"
 	l "avito-shop/pkg/logger"
 	_ "avito-shop/pkg/logger/handlers/slogpretty"
 	"avito-shop/pkg/logger/sl"
 	"avito-shop/pkg/postgres"
-)
-
-const (
-	numWorkers = 18
-	taskNum    = 18
+	"avito-shop/pkg/tracing"
 )
 
 func Run(cfg *config.Config) {
@@ -33,6 +58,8 @@ func Run(cfg *config.Config) {
 
 	// logger
 	log := l.SetupLogger(cfg.Env)
+	// Use cases log through the request-scoped logger and fall back to this one.
+	slog.SetDefault(log)
 
 	log.Info(
 		"starting avito-shop",
@@ -41,28 +68,182 @@ func Run(cfg *config.Config) {
 	)
 	log.Debug("debug messages are enabled")
 
+	// Auth tokens carry the admin role, so refuse to start without a key
+	if err := jwt.SetKey(cfg.JWT.Secret); err != nil {
+		log.Error("failed to init jwt", sl.Err(err))
+		os.Exit(-1)
+	}
+
+	// Tracing
+	tracer, err := tracing.New(context.Background(), cfg.App.Name, cfg.App.Version,
+		tracing.Exporter(cfg.Tracing.Exporter),
+		tracing.Endpoint(cfg.Tracing.Endpoint),
+		tracing.SampleRatio(cfg.Tracing.SampleRatio),
+	)
+	if err != nil {
+		log.Error("failed to init tracing", sl.Err(err))
+		os.Exit(-1)
+	}
+
 	// Repository
 	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PoolMax))
 	if err != nil {
 		log.Error("failed to init storage", sl.Err(err))
 		os.Exit(-1)
 	}
-	defer pg.Close()
 
 	// Workers
-	workerPool := worker.NewWorkerPool(numWorkers, taskNum)
-	defer workerPool.Shutdown()
+	workerPools := newWorkerPools(cfg, log)
 
-	// HTTP Server
-	handler := gin.New()
-	controller.NewRouter(handler,
+	// Metrics
+	prometheus.MustRegister(postgres.NewCollector(pg), worker.NewCollector(workerPools))
+
+	// Migrations the binary expects, for /readyz
+	migration, err := latestMigration(cfg.Health.MigrationsDir)
+	if err != nil {
+		log.Error("failed to read migrations", sl.Err(err))
+		os.Exit(-1)
+	}
+
+	// Use cases are built once and shared by the HTTP handlers and the
+	// background jobs, so they all go through the same transaction manager
+	// and apply the same policies
+	trManager := postgres.NewRetryManager(manager.Must(trmpgx.NewDefaultFactory(pg.Pool)))
+
+	balanceRepo := repo.NewBalanceRepo(pg)
+	grantRepo := repo.NewGrantRepo(pg)
+	ledgerRepo := repo.NewLedgerRepo(pg)
+	lotRepo := repo.NewLotRepo(pg)
+	transactionRepo := repo.NewTransactionRepo(pg)
+	inventoryRepo := repo.NewInventoryRepo(pg)
+
+	sendUseCase := send.New(
+		balanceRepo,
+		transactionRepo,
+		ledgerRepo,
+		lotRepo,
+		repo.NewPendingTransferRepo(pg),
+		trManager,
+		entity.SendPolicy{
+			DailyLimit:        cfg.SendPolicy.DailyLimit,
+			MonthlyLimit:      cfg.SendPolicy.MonthlyLimit,
+			ApprovalThreshold: cfg.SendPolicy.ApprovalThreshold,
+		},
+	)
+
+	scheduleUseCase := schedule.New(repo.NewScheduleRepo(pg), balanceRepo, sendUseCase, trManager)
+	statsUseCase := stats.New(repo.NewStatsRepo(pg), trManager)
+	analyticsUseCase := analytics.New(repo.NewLeaderboardRepo(pg), cfg.Leaderboard.Size)
+
+	useCases := controller.UseCases{
+		Analytics: analyticsUseCase,
+		Approval:  sendUseCase,
+		Auth: auth.New(
+			repo.NewUserRepo(pg),
+			balanceRepo,
+			grantRepo,
+			ledgerRepo,
+			lotRepo,
+			trManager,
+			cfg.Onboarding.Grant,
+		),
+		Buy: buy.New(balanceRepo, inventoryRepo, ledgerRepo, lotRepo, trManager),
+		CoinRequest: coinrequest.New(
+			repo.NewCoinRequestRepo(pg),
+			balanceRepo,
+			sendUseCase,
+			trManager,
+			cfg.CoinRequest.TTL,
+		),
+		Grant:       grant.New(grantRepo, balanceRepo, ledgerRepo, lotRepo, trManager),
+		Health:      health.New(repo.NewHealthRepo(pg), migration),
+		History:     history.New(transactionRepo),
+		Idempotency: idempotency.New(repo.NewIdempotencyRepo(pg), trManager),
+		Info:        info.New(balanceRepo, inventoryRepo, transactionRepo, lotRepo, trManager),
+		Reversal:    reversal.New(transactionRepo, balanceRepo, ledgerRepo, lotRepo, trManager),
+		Schedule:    scheduleUseCase,
+		Send:        sendUseCase,
+		Statement:   statement.New(ledgerRepo),
+		Stats:       statsUseCase,
+	}
+
+	// Scheduler
+	transferScheduler := scheduler.New(scheduleUseCase, log, cfg.Scheduler.Interval)
+	transferScheduler.Start()
+
+	// Reconciliation
+	balanceReconciler := reconciler.New(
+		reconcile.New(repo.NewReconciliationRepo(pg), trManager),
+		log,
+		cfg.Reconciliation.Interval,
+	)
+	balanceReconciler.Start()
+
+	// Coin expiry
+	coinExpirer := expirer.New(
+		expiry.New(lotRepo, balanceRepo, ledgerRepo, trManager, cfg.CoinExpiry.TTL),
 		log,
-		pg,
-		workerPool,
+		cfg.CoinExpiry.Interval,
 	)
+	coinExpirer.Start()
+
+	// Leaderboards are cached and shared with the HTTP handlers
+	leaderboardRefresher := refresher.New(analyticsUseCase, log, cfg.Leaderboard.RefreshInterval)
+	leaderboardRefresher.Start()
+
+	// Daily stats rollups
+	statsAggregator := aggregator.New(statsUseCase, log, cfg.Stats.RollupInterval)
+	statsAggregator.Start()
+
+	// Probes: /readyz fails once shutdown starts
+	var ready atomic.Bool
+	ready.Store(true)
+
+	// HTTP Server
+	handler := gin.New()
+	controller.NewRouter(handler, log, workerPools, useCases, &ready)
 
 	// run server
-	httpServer := httpserver.New(handler, httpserver.Port(cfg.HTTP.Port))
+	httpServer := httpserver.New(handler,
+		httpserver.Port(cfg.HTTP.Port),
+		httpserver.ShutdownTimeout(cfg.Shutdown.HTTP),
+	)
+
+	// Metrics are not public: they get their own listener on the internal port.
+	internalHandler := http.NewServeMux()
+	controller.NewInternalRouter(internalHandler)
+
+	internalServer := httpserver.New(internalHandler,
+		httpserver.Port(cfg.HTTP.InternalPort),
+		httpserver.ShutdownTimeout(cfg.Shutdown.HTTP),
+	)
+
+	// Shutdown order: fail readiness and let the load balancer notice, stop
+	// accepting connections and drain in-flight requests, drain the worker
+	// pools, stop the background jobs and the metrics listener, then close
+	// Postgres and flush traces.
+	shutdown := lifecycle.New(log)
+	shutdown.Add("readiness", 0, func(context.Context) error {
+		ready.Store(false)
+		log.Info("draining traffic", slog.Duration("delay", cfg.Health.DrainDelay))
+		time.Sleep(cfg.Health.DrainDelay)
+
+		return nil
+	})
+	shutdown.Add("http", cfg.Shutdown.HTTP, func(context.Context) error {
+		return httpServer.Shutdown()
+	})
+	shutdown.Add("workers", cfg.Shutdown.Workers, lifecycle.Func(workerPools.Shutdown))
+	shutdown.Add("scheduler", cfg.Shutdown.Jobs, lifecycle.Func(transferScheduler.Shutdown))
+	shutdown.Add("reconciler", cfg.Shutdown.Jobs, lifecycle.Func(balanceReconciler.Shutdown))
+	shutdown.Add("expirer", cfg.Shutdown.Jobs, lifecycle.Func(coinExpirer.Shutdown))
+	shutdown.Add("refresher", cfg.Shutdown.Jobs, lifecycle.Func(leaderboardRefresher.Shutdown))
+	shutdown.Add("aggregator", cfg.Shutdown.Jobs, lifecycle.Func(statsAggregator.Shutdown))
+	shutdown.Add("internal http", cfg.Shutdown.HTTP, func(context.Context) error {
+		return internalServer.Shutdown()
+	})
+	shutdown.Add("postgres", cfg.Shutdown.Postgres, lifecycle.Func(pg.Close))
+	shutdown.Add("tracing", cfg.Shutdown.Tracing, tracer.Shutdown)
 
 	// Waiting signal
 	interrupt := make(chan os.Signal, 1)
@@ -73,15 +254,38 @@ func Run(cfg *config.Config) {
 		log.Info(op + s.String())
 	case err = <-httpServer.Notify():
 		log.Error(op, err)
+	case err = <-internalServer.Notify():
+		log.Error(op, err)
 	}
 
 	// Shutdown
-	workerPool.Shutdown()
-
-	err = httpServer.Shutdown()
+	err = shutdown.Shutdown()
 	if err != nil {
-		log.Error("failed to stop server", fmt.Errorf("%s: %w", op, err))
+		log.Error("graceful shutdown incomplete", slog.String("op", op), sl.Err(err))
 	}
 
 	log.Info("server stopped")
 }
+
+func newWorkerPools(cfg *config.Config, log *slog.Logger) *worker.Pools {
+	newPool := func(name string, size config.WorkerPool) *worker.Pool {
+		return worker.NewWorkerPool(size.Workers, size.Queue,
+			worker.Name(name),
+			worker.EnqueueTimeout(cfg.Workers.EnqueueTimeout),
+			worker.PanicHandler(func(err error) {
+				var panicErr *worker.PanicError
+				if errors.As(err, &panicErr) {
+					log.Error("worker recovered from panic",
+						slog.String("pool", name), sl.Err(err), slog.String("stack", string(panicErr.Stack)))
+				}
+			}),
+		)
+	}
+
+	return &worker.Pools{
+		Auth:    newPool("auth", cfg.Workers.Auth),
+		Writes:  newPool("writes", cfg.Workers.Writes),
+		Reads:   newPool("reads", cfg.Workers.Reads),
+		Exports: newPool("exports", cfg.Workers.Exports),
+	}
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/config"
+	"avito-shop/internal/controller/reconciler"
+	"avito-shop/internal/entity"
+	repo "avito-shop/internal/repository"
+	"avito-shop/internal/usecase/auth"
+	"avito-shop/internal/usecase/ledger"
+	"avito-shop/internal/usecase/reconcile"
+	l "avito-shop/pkg/logger"
+	"avito-shop/pkg/logger/sl"
+	"avito-shop/pkg/postgres"
+)
+
+// RecomputeBalances rebuilds the Balance table from the ledger and exits with
+// a non-zero code if it fails.
+func RecomputeBalances(cfg *config.Config) {
+	log := l.SetupLogger(cfg.Env)
+
+	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PoolMax))
+	if err != nil {
+		log.Error("failed to init storage", sl.Err(err))
+		os.Exit(-1)
+	}
+	defer pg.Close()
+
+	trManager := postgres.NewRetryManager(manager.Must(trmpgx.NewDefaultFactory(pg.Pool)))
+	ledgerUseCase := ledger.New(repo.NewLedgerRepo(pg), repo.NewBalanceRepo(pg), trManager)
+
+	fixed, err := ledgerUseCase.RecomputeBalances(context.Background())
+	if err != nil {
+		log.Error("failed to recompute balances", sl.Err(err))
+		pg.Close()
+		os.Exit(1)
+	}
+
+	log.Info("balances recomputed from ledger", slog.Int("fixed", fixed))
+}
+
+// Reconcile runs a single balance reconciliation and exits with a non-zero
+// code if it fails.
+func Reconcile(cfg *config.Config) {
+	log := l.SetupLogger(cfg.Env)
+
+	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PoolMax))
+	if err != nil {
+		log.Error("failed to init storage", sl.Err(err))
+		os.Exit(-1)
+	}
+	defer pg.Close()
+
+	trManager := postgres.NewRetryManager(manager.Must(trmpgx.NewDefaultFactory(pg.Pool)))
+	balanceReconciler := reconciler.New(reconcile.New(repo.NewReconciliationRepo(pg), trManager), log, time.Hour)
+
+	if err = balanceReconciler.RunOnce(context.Background()); err != nil {
+		pg.Close()
+		os.Exit(1)
+	}
+}
+
+// CreateAdmin provisions an admin account, or grants the admin role to an
+// existing user, and exits. The password of a new account is read from the
+// ADMIN_PASSWORD environment variable so it stays out of the shell history.
+func CreateAdmin(cfg *config.Config, username string) {
+	log := l.SetupLogger(cfg.Env)
+
+	password := os.Getenv("ADMIN_PASSWORD")
+	if username == "" || password == "" {
+		log.Error("failed to create admin", sl.Err(errors.New("username argument and ADMIN_PASSWORD are required")))
+		os.Exit(1)
+	}
+
+	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PoolMax))
+	if err != nil {
+		log.Error("failed to init storage", sl.Err(err))
+		os.Exit(-1)
+	}
+	defer pg.Close()
+
+	trManager := postgres.NewRetryManager(manager.Must(trmpgx.NewDefaultFactory(pg.Pool)))
+	authUseCase := auth.New(
+		repo.NewUserRepo(pg),
+		repo.NewBalanceRepo(pg),
+		repo.NewGrantRepo(pg),
+		repo.NewLedgerRepo(pg),
+		repo.NewLotRepo(pg),
+		trManager,
+		cfg.Onboarding.Grant,
+	)
+
+	if err = authUseCase.CreateAdmin(context.Background(), entity.User{Username: username, Password: password}); err != nil {
+		log.Error("failed to create admin", sl.Err(err))
+		pg.Close()
+		os.Exit(1)
+	}
+}
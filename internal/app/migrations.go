@@ -0,0 +1,46 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// latestMigration returns the version of the newest migration in dir. Files
+// are named as golang-migrate expects: <version>_<title>.up.sql.
+func latestMigration(dir string) (uint, error) {
+	const op = "app.latestMigration"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var latest uint
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+
+		prefix, _, found := strings.Cut(name, "_")
+		if !found {
+			return 0, fmt.Errorf("%s: unexpected migration name %q", op, name)
+		}
+
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s: unexpected migration name %q: %w", op, name, err)
+		}
+
+		latest = max(latest, uint(version))
+	}
+
+	if latest == 0 {
+		return 0, fmt.Errorf("%s: no migrations in %s", op, dir)
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,43 @@
+package aggregator
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/usecase/stats"
+	"avito-shop/pkg/logger/sl"
+	"avito-shop/pkg/periodic"
+)
+
+// Aggregator periodically materializes the daily coin economy rollups. Start
+// catches up on missing days right away and then runs on every tick.
+type Aggregator struct {
+	*periodic.Runner
+	statsUC stats.Stats
+	log     *slog.Logger
+}
+
+func New(statsUC stats.Stats, log *slog.Logger, interval time.Duration) *Aggregator {
+	a := &Aggregator{
+		statsUC: statsUC,
+		log:     log,
+	}
+	a.Runner = periodic.New(interval, a.tick, periodic.Immediately())
+
+	return a
+}
+
+func (a *Aggregator) tick(ctx context.Context) {
+	const op = "aggregator.tick"
+
+	n, err := a.statsUC.RollUp(ctx, time.Now())
+	if err != nil {
+		a.log.Error("failed to roll up daily stats", slog.String("op", op), sl.Err(err))
+	}
+
+	if n > 0 {
+		a.log.Info("daily stats rolled up", slog.String("op", op), slog.Int("days", n))
+	}
+}
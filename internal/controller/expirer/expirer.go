@@ -0,0 +1,42 @@
+package expirer
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/usecase/expiry"
+	"avito-shop/pkg/logger/sl"
+	"avito-shop/pkg/periodic"
+)
+
+// Expirer periodically burns coins that outlived their lifetime.
+type Expirer struct {
+	*periodic.Runner
+	expiryUC expiry.Expiry
+	log      *slog.Logger
+}
+
+func New(expiryUC expiry.Expiry, log *slog.Logger, interval time.Duration) *Expirer {
+	x := &Expirer{
+		expiryUC: expiryUC,
+		log:      log,
+	}
+	x.Runner = periodic.New(interval, x.tick)
+
+	return x
+}
+
+func (x *Expirer) tick(ctx context.Context) {
+	const op = "expirer.tick"
+
+	n, err := x.expiryUC.ExpireDue(ctx, time.Now())
+	if err != nil {
+		x.log.Error("failed to expire coins", slog.String("op", op), sl.Err(err))
+	}
+
+	if n > 0 {
+		x.log.Info("expired coins burned", slog.String("op", op), slog.Int("users", n))
+	}
+}
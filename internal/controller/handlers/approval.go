@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	"avito-shop/internal/entity"
+	"avito-shop/internal/usecase/send"
+	e "avito-shop/pkg/errors"
+	mw "avito-shop/pkg/jwt"
+)
+
+type ApprovalRoute struct {
+	approvalUC send.Approval
+	log        *slog.Logger
+	wp         worker.PoolI
+}
+
+func NewApprovalRoute(handler *gin.RouterGroup, approvalUC send.Approval, wp worker.PoolI, log *slog.Logger) {
+	r := &ApprovalRoute{approvalUC, log, wp}
+
+	g := handler.Group("/admin/pendingTransfers", mw.AuthMW(), mw.AdminMW())
+	{
+		g.GET("", r.List)
+		g.POST("/:id/approve", r.Approve)
+		g.POST("/:id/reject", r.Reject)
+	}
+}
+
+type PendingTransferURI struct {
+	ID int `uri:"id" binding:"required,gt=0"`
+}
+
+func (r *ApprovalRoute) List(c *gin.Context) {
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) ([]entity.PendingTransfer, error) {
+		return r.approvalUC.ListPending(ctx)
+	})
+	if err != nil {
+		r.fail(c, "Failed to list pending transfers", err)
+
+		return
+	}
+
+	if result == nil {
+		result = []entity.PendingTransfer{}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (r *ApprovalRoute) Approve(c *gin.Context) {
+	r.answer(c, "Failed to approve transfer", r.approvalUC.Approve)
+}
+
+func (r *ApprovalRoute) Reject(c *gin.Context) {
+	r.answer(c, "Failed to reject transfer", r.approvalUC.Reject)
+}
+
+func (r *ApprovalRoute) answer(c *gin.Context,
+	failMsg string,
+	fn func(ctx context.Context, admin string, id int) (*entity.PendingTransfer, error),
+) {
+	admin, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var uri PendingTransferURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (*entity.PendingTransfer, error) {
+		return fn(ctx, admin.(string), uri.ID)
+	})
+	if err != nil {
+		r.fail(c, failMsg, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (r *ApprovalRoute) fail(c *gin.Context, failMsg string, err error) {
+	requestLog(c, r.log).Error(failMsg, slog.String("error", err.Error()))
+
+	if abortUnavailable(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, e.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pending transfer or user not found"})
+	case errors.Is(err, e.ErrInsufficientFunds):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient funds"})
+	case errors.Is(err, e.ErrAlreadyResolved):
+		c.JSON(http.StatusConflict, gin.H{"error": "Transfer already resolved"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failMsg})
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	workermocks "avito-shop/internal/controller/worker/mocks"
+	"avito-shop/internal/entity"
+	sendmocks "avito-shop/internal/usecase/send/mocks"
+	e "avito-shop/pkg/errors"
+)
+
+func TestApprovalRoute_Approve_Success(t *testing.T) {
+	mockApprovalUC := new(sendmocks.Approval)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	createdAt := time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC)
+	resolvedAt := createdAt.Add(time.Hour)
+	mockApprovalUC.On("Approve", mock.Anything, "admin", 3).Return(&entity.PendingTransfer{
+		ID:         3,
+		FromUser:   "alice",
+		ToUser:     "bob",
+		Amount:     5000,
+		Status:     entity.PendingTransferApproved,
+		CreatedAt:  createdAt,
+		ResolvedAt: &resolvedAt,
+		ResolvedBy: "admin",
+	}, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/pendingTransfers/3/approve", http.NoBody)
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+	c.Set("username", "admin")
+
+	approvalRoute := &ApprovalRoute{
+		approvalUC: mockApprovalUC,
+		wp:         mockWorkerPool,
+		log:        log,
+	}
+
+	approvalRoute.Approve(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"id": 3,
+		"fromUser": "alice",
+		"toUser": "bob",
+		"amount": 5000,
+		"status": "approved",
+		"createdAt": "2030-01-01T12:00:00Z",
+		"resolvedAt": "2030-01-01T13:00:00Z",
+		"resolvedBy": "admin"
+	}`, w.Body.String())
+
+	mockApprovalUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestApprovalRoute_Reject_AlreadyResolved(t *testing.T) {
+	mockApprovalUC := new(sendmocks.Approval)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockApprovalUC.On("Reject", mock.Anything, "admin", 3).Return(nil, e.ErrAlreadyResolved)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/pendingTransfers/3/reject", http.NoBody)
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+	c.Set("username", "admin")
+
+	approvalRoute := &ApprovalRoute{
+		approvalUC: mockApprovalUC,
+		wp:         mockWorkerPool,
+		log:        log,
+	}
+
+	approvalRoute.Reject(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error": "Transfer already resolved"}`, w.Body.String())
+
+	mockApprovalUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -34,42 +35,41 @@ type AuthResponse struct {
 }
 
 func (r *AuthRoute) Auth(c *gin.Context) {
-	resultChan := make(chan AuthResponse, 1)
-	errorChan := make(chan error, 1)
+	var req AuthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLog(c, r.log).Error("Authentication failed", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 
-	r.wp.Submit(func() {
-		var req AuthRequest
-
-		if err := c.ShouldBindJSON(&req); err != nil {
-			errorChan <- err
-
-			return
-		}
+		return
+	}
 
-		token, err := r.authUC.Login(c.Request.Context(), entity.User{
+	authResponse, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (AuthResponse, error) {
+		token, err := r.authUC.Login(ctx, entity.User{
 			Username: req.Username,
 			Password: req.Password,
 		})
 		if err != nil {
-			errorChan <- err
-
-			return
+			return AuthResponse{}, err
 		}
 
-		resultChan <- AuthResponse{Token: token}
+		return AuthResponse{Token: token}, nil
 	})
+	if err != nil {
+		requestLog(c, r.log).Error("Authentication failed", slog.String("error", err.Error()))
 
-	select {
-	case authResponse := <-resultChan:
-		c.JSON(http.StatusOK, authResponse)
-	case err := <-errorChan:
-		r.log.Error("Authentication failed", slog.String("error", err.Error()))
+		if abortUnavailable(c, err) {
+			return
+		}
 
 		switch {
 		case errors.Is(err, e.ErrInvalidCredentials), errors.Is(err, e.ErrNotFound):
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credentials"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 		}
+
+		return
 	}
+
+	c.JSON(http.StatusOK, authResponse)
 }
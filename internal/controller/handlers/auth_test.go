@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -16,6 +16,7 @@ import (
 	worker_mocks "avito-shop/internal/controller/worker/mocks"
 	"avito-shop/internal/entity"
 	auth_mocks "avito-shop/internal/usecase/auth/mocks"
+	e "avito-shop/pkg/errors"
 )
 
 func TestAuthRoute_Auth(t *testing.T) {
@@ -32,10 +33,10 @@ func TestAuthRoute_Auth(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodPost, "/auth", strings.NewReader(reqBody))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	mockWorkerPool.On("Submit", mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
-		task := args.Get(0).(worker.Task)
-		task()
-	}).Return()
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
 	mockAuthUC.On("Login", mock.Anything, entity.User{Username: "testuser", Password: "testpass"}).Return("testtoken", nil)
 
 	authRoute := &AuthRoute{authUC: mockAuthUC, wp: mockWorkerPool, log: log}
@@ -62,11 +63,11 @@ func TestAuthRoute_Auth_InvalidCredentials(t *testing.T) {
 	c.Request = httptest.NewRequest(http.MethodPost, "/auth", strings.NewReader(reqBody))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	mockWorkerPool.On("Submit", mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
-		task := args.Get(0).(worker.Task)
-		task()
-	}).Return()
-	mockAuthUC.On("Login", mock.Anything, entity.User{Username: "testuser", Password: "wrongpass"}).Return("", errors.New("invalid credentials"))
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+	mockAuthUC.On("Login", mock.Anything, entity.User{Username: "testuser", Password: "wrongpass"}).Return("", e.ErrInvalidCredentials)
 
 	authRoute := &AuthRoute{authUC: mockAuthUC, wp: mockWorkerPool, log: log}
 	authRoute.Auth(c)
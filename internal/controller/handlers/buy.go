@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -9,18 +10,25 @@ import (
 
 	"avito-shop/internal/controller/worker"
 	"avito-shop/internal/usecase/buy"
+	"avito-shop/internal/usecase/idempotency"
 	e "avito-shop/pkg/errors"
 	mw "avito-shop/pkg/jwt"
 )
 
 type BuyRoute struct {
-	buyUC buy.Buy
-	log   *slog.Logger
-	wp    worker.PoolI
+	buyUC         buy.Buy
+	idempotencyUC idempotency.Idempotency
+	log           *slog.Logger
+	wp            worker.PoolI
 }
 
-func NewBuyRoute(handler *gin.RouterGroup, buyUC buy.Buy, wp worker.PoolI, log *slog.Logger) {
-	r := &BuyRoute{buyUC, log, wp}
+func NewBuyRoute(handler *gin.RouterGroup,
+	buyUC buy.Buy,
+	idempotencyUC idempotency.Idempotency,
+	wp worker.PoolI,
+	log *slog.Logger,
+) {
+	r := &BuyRoute{buyUC, idempotencyUC, log, wp}
 	handler.GET("/buy/:item", mw.AuthMW(), r.Buy)
 }
 
@@ -29,12 +37,9 @@ type BuyRequest struct {
 }
 
 func (r *BuyRoute) Buy(c *gin.Context) {
-	resultChan := make(chan string, 1)
-	errorChan := make(chan error, 1)
-
 	username, exists := c.Get("username")
 	if !exists {
-		r.log.Error("Username not found in context")
+		requestLog(c, r.log).Error("Username not found in context")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 
 		return
@@ -42,34 +47,48 @@ func (r *BuyRoute) Buy(c *gin.Context) {
 
 	var req BuyRequest
 	if err := c.ShouldBindUri(&req); err != nil {
-		r.log.Error("Failed to parse request", slog.String("error", err.Error()))
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 
 		return
 	}
 
-	r.wp.Submit(func() {
-		err := r.buyUC.BuyItem(c.Request.Context(), username.(string), req.Item)
-		if err != nil {
-			errorChan <- err
+	key, ok := idempotencyKey(c, username.(string), req)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid idempotency key"})
 
-			return
-		}
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (storedResponse, error) {
+		return runIdempotent(ctx, r.idempotencyUC, key, func(ctx context.Context) (int, []byte, error) {
+			if err := r.buyUC.BuyItem(ctx, username.(string), req.Item); err != nil {
+				return 0, nil, err
+			}
 
-		resultChan <- "Item purchased successfully"
+			return jsonResponse(http.StatusOK, "Item purchased successfully")
+		})
 	})
+	if err != nil {
+		requestLog(c, r.log).Error("Failed to buy item", slog.String("error", err.Error()))
 
-	select {
-	case result := <-resultChan:
-		c.JSON(http.StatusOK, result) // Успешный ответ
-	case err := <-errorChan:
-		r.log.Error("Failed to buy item", slog.String("error", err.Error()))
+		if abortUnavailable(c, err) {
+			return
+		}
 
 		switch {
+		case errors.Is(err, e.ErrInsufficientFunds):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient funds"})
 		case errors.Is(err, e.ErrInvalidCredentials), errors.Is(err, e.ErrNotFound):
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credentials"})
+		case errors.Is(err, e.ErrIdempotencyKeyReused):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency key reused with different request"})
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to buy item"})
 		}
+
+		return
 	}
+
+	result.write(c) // Успешный ответ
 }
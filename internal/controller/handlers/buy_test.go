@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -9,11 +13,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/slog"
 
 	"avito-shop/internal/controller/worker"
 	worker_mocks "avito-shop/internal/controller/worker/mocks"
 	buy_mocks "avito-shop/internal/usecase/buy/mocks"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/logger"
+	"avito-shop/pkg/tracing"
 )
 
 func TestBuyRoute_Buy_Success(t *testing.T) {
@@ -21,10 +29,10 @@ func TestBuyRoute_Buy_Success(t *testing.T) {
 	mockWorkerPool := new(worker_mocks.PoolI)
 	log := slog.Default()
 
-	mockWorkerPool.On("Submit", mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
-		task := args.Get(0).(worker.Task)
-		task()
-	}).Return()
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
 
 	mockBuyUC.On("BuyItem", mock.Anything, "testuser", "testitem").Return(nil)
 
@@ -107,10 +115,10 @@ func TestBuyRoute_Buy_InternalError(t *testing.T) {
 	mockWorkerPool := new(worker_mocks.PoolI)
 	log := slog.Default()
 
-	mockWorkerPool.On("Submit", mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
-		task := args.Get(0).(worker.Task)
-		task()
-	}).Return()
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
 
 	mockBuyUC.On("BuyItem", mock.Anything, "testuser", "testitem").Return(errors.New("internal error"))
 
@@ -137,3 +145,205 @@ func TestBuyRoute_Buy_InternalError(t *testing.T) {
 	mockBuyUC.AssertExpectations(t)
 	mockWorkerPool.AssertExpectations(t)
 }
+
+func TestBuyRoute_Buy_InsufficientFunds(t *testing.T) {
+	mockBuyUC := new(buy_mocks.Buy)
+	mockWorkerPool := new(worker_mocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockBuyUC.On("BuyItem", mock.Anything, "testuser", "pink-hoody").
+		Return(fmt.Errorf("usecase.BuyItem: %w", e.ErrInsufficientFunds))
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/buy/pink-hoody", http.NoBody)
+	c.Params = gin.Params{gin.Param{Key: "item", Value: "pink-hoody"}}
+	c.Set("username", "testuser")
+
+	buyRoute := &BuyRoute{
+		buyUC: mockBuyUC,
+		wp:    mockWorkerPool,
+		log:   log,
+	}
+
+	buyRoute.Buy(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"Insufficient funds"}`, w.Body.String())
+
+	mockBuyUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestBuyRoute_Buy_PoolSaturated(t *testing.T) {
+	mockBuyUC := new(buy_mocks.Buy)
+	mockWorkerPool := new(worker_mocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Return(e.ErrPoolSaturated)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/buy/testitem", http.NoBody)
+	c.Params = gin.Params{gin.Param{Key: "item", Value: "testitem"}}
+	c.Set("username", "testuser")
+
+	buyRoute := &BuyRoute{
+		buyUC: mockBuyUC,
+		wp:    mockWorkerPool,
+		log:   log,
+	}
+
+	buyRoute.Buy(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error": "Service is busy, retry later"}`, w.Body.String())
+
+	mockBuyUC.AssertNotCalled(t, "BuyItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBuyRoute_Buy_ClientGone(t *testing.T) {
+	mockBuyUC := new(buy_mocks.Buy)
+	mockWorkerPool := new(worker_mocks.PoolI)
+	log := slog.Default()
+
+	// The task is queued but never picked up, as with a stuck pool.
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Return(nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/buy/testitem", http.NoBody).WithContext(ctx)
+	c.Params = gin.Params{gin.Param{Key: "item", Value: "testitem"}}
+	c.Set("username", "testuser")
+
+	buyRoute := &BuyRoute{
+		buyUC: mockBuyUC,
+		wp:    mockWorkerPool,
+		log:   log,
+	}
+
+	buyRoute.Buy(c)
+
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, statusClientClosedRequest, c.Writer.Status())
+	mockBuyUC.AssertNotCalled(t, "BuyItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBuyRoute_Buy_PropagatesTraceparent(t *testing.T) {
+	mockBuyUC := new(buy_mocks.Buy)
+	mockWorkerPool := new(worker_mocks.PoolI)
+	log := slog.Default()
+
+	_, err := tracing.New(context.Background(), "avito-shop", "test")
+	assert.NoError(t, err)
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockBuyUC.On("BuyItem", mock.MatchedBy(func(ctx context.Context) bool {
+		return trace.SpanContextFromContext(ctx).TraceID().String() == "4bf92f3577b34da6a3ce929d0e0e4736"
+	}), "testuser", "testitem").Return(nil)
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(tracing.HTTP(), func(c *gin.Context) {
+		c.Set("username", "testuser")
+	})
+
+	buyRoute := &BuyRoute{
+		buyUC: mockBuyUC,
+		wp:    mockWorkerPool,
+		log:   log,
+	}
+	router.GET("/buy/:item", buyRoute.Buy)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/buy/testitem", http.NoBody)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockBuyUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestBuyRoute_Buy_RequestID(t *testing.T) {
+	mockBuyUC := new(buy_mocks.Buy)
+	mockWorkerPool := new(worker_mocks.PoolI)
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockBuyUC.On("BuyItem", mock.MatchedBy(func(ctx context.Context) bool {
+		return logger.FromContext(ctx, nil) != nil
+	}), "testuser", "testitem").Return(nil)
+
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(logger.RequestID(log), logger.AccessLog(log), func(c *gin.Context) {
+		c.Set("username", "testuser")
+	})
+
+	buyRoute := &BuyRoute{
+		buyUC: mockBuyUC,
+		wp:    mockWorkerPool,
+		log:   log,
+	}
+	router.GET("/buy/:item", buyRoute.Buy)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/buy/testitem", http.NoBody)
+	req.Header.Set(logger.RequestIDHeader, "req-123")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "req-123", w.Header().Get(logger.RequestIDHeader))
+
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "request", line["msg"])
+	assert.Equal(t, "req-123", line["request_id"])
+	assert.Equal(t, "/buy/:item", line["route"])
+	assert.EqualValues(t, http.StatusOK, line["status"])
+
+	// A forged ID is replaced with a generated one.
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/buy/testitem", http.NoBody)
+	req.Header.Set(logger.RequestIDHeader, "bad\nid")
+
+	router.ServeHTTP(w, req)
+
+	assert.Regexp(t, `^[0-9a-f]{32}$`, w.Header().Get(logger.RequestIDHeader))
+
+	mockBuyUC.AssertExpectations(t)
+}
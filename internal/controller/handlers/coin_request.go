@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	"avito-shop/internal/entity"
+	"avito-shop/internal/usecase/coinrequest"
+	e "avito-shop/pkg/errors"
+	mw "avito-shop/pkg/jwt"
+)
+
+const directionOutgoing = "outgoing"
+
+type CoinRequestRoute struct {
+	coinRequestUC coinrequest.CoinRequest
+	log           *slog.Logger
+	wp            worker.PoolI
+}
+
+func NewCoinRequestRoute(handler *gin.RouterGroup, coinRequestUC coinrequest.CoinRequest, wp worker.PoolI, log *slog.Logger) {
+	r := &CoinRequestRoute{coinRequestUC, log, wp}
+
+	g := handler.Group("/coinRequests", mw.AuthMW())
+	{
+		g.POST("", r.Create)
+		g.GET("", r.List)
+		g.POST("/:id/accept", r.Accept)
+		g.POST("/:id/decline", r.Decline)
+	}
+}
+
+type CoinRequestRequest struct {
+	Payer  string `json:"payer"  binding:"required"`
+	Amount int    `json:"amount" binding:"required,gt=0,lte=1000000"`
+	Note   string `json:"note"   binding:"omitempty,max=200"`
+}
+
+type CoinRequestQuery struct {
+	Direction string `form:"direction" binding:"omitempty,oneof=incoming outgoing"`
+}
+
+type CoinRequestURI struct {
+	ID int `uri:"id" binding:"required,gt=0"`
+}
+
+func (r *CoinRequestRoute) Create(c *gin.Context) {
+	username, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var req CoinRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (*entity.CoinRequest, error) {
+		return r.coinRequestUC.Create(ctx, entity.CoinRequest{
+			Requester: username.(string),
+			Payer:     req.Payer,
+			Amount:    req.Amount,
+			Note:      req.Note,
+		})
+	})
+	if err != nil {
+		r.fail(c, "Failed to create coin request", err)
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+func (r *CoinRequestRoute) List(c *gin.Context) {
+	username, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var query CoinRequestQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) ([]entity.CoinRequest, error) {
+		if query.Direction == directionOutgoing {
+			return r.coinRequestUC.ListOutgoing(ctx, username.(string))
+		}
+
+		return r.coinRequestUC.ListIncoming(ctx, username.(string))
+	})
+	if err != nil {
+		r.fail(c, "Failed to list coin requests", err)
+
+		return
+	}
+
+	if result == nil {
+		result = []entity.CoinRequest{}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (r *CoinRequestRoute) Accept(c *gin.Context) {
+	r.answer(c, "Failed to accept coin request", r.coinRequestUC.Accept)
+}
+
+func (r *CoinRequestRoute) Decline(c *gin.Context) {
+	r.answer(c, "Failed to decline coin request", r.coinRequestUC.Decline)
+}
+
+func (r *CoinRequestRoute) answer(c *gin.Context,
+	failMsg string,
+	fn func(ctx context.Context, username string, id int) (*entity.CoinRequest, error),
+) {
+	username, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var uri CoinRequestURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (*entity.CoinRequest, error) {
+		return fn(ctx, username.(string), uri.ID)
+	})
+	if err != nil {
+		r.fail(c, failMsg, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (r *CoinRequestRoute) fail(c *gin.Context, failMsg string, err error) {
+	requestLog(c, r.log).Error(failMsg, slog.String("error", err.Error()))
+
+	if abortUnavailable(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, e.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Coin request or user not found"})
+	case errors.Is(err, e.ErrInvalidRequest):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+	case errors.Is(err, e.ErrInsufficientFunds):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient funds"})
+	case errors.Is(err, e.ErrAlreadyResolved):
+		c.JSON(http.StatusConflict, gin.H{"error": "Coin request already resolved"})
+	case errors.Is(err, e.ErrExpired):
+		c.JSON(http.StatusGone, gin.H{"error": "Coin request expired"})
+	case errors.Is(err, e.ErrApprovalRequired):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Payment exceeds the approval threshold, send it as a transfer instead"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failMsg})
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	workermocks "avito-shop/internal/controller/worker/mocks"
+	"avito-shop/internal/entity"
+	coinrequestmocks "avito-shop/internal/usecase/coinrequest/mocks"
+	e "avito-shop/pkg/errors"
+)
+
+func TestCoinRequestRoute_Create_Success(t *testing.T) {
+	mockCoinRequestUC := new(coinrequestmocks.CoinRequest)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	createdAt := time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC)
+	mockCoinRequestUC.On("Create", mock.Anything, entity.CoinRequest{
+		Requester: "alice",
+		Payer:     "bob",
+		Amount:    30,
+		Note:      "lunch",
+	}).Return(&entity.CoinRequest{
+		ID:        1,
+		Requester: "alice",
+		Payer:     "bob",
+		Amount:    30,
+		Note:      "lunch",
+		Status:    entity.CoinRequestPending,
+		CreatedAt: createdAt,
+		ExpiresAt: createdAt.Add(7 * 24 * time.Hour),
+	}, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"payer": "bob", "amount": 30, "note": "lunch"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/coinRequests", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "alice")
+
+	coinRequestRoute := &CoinRequestRoute{
+		coinRequestUC: mockCoinRequestUC,
+		wp:            mockWorkerPool,
+		log:           log,
+	}
+
+	coinRequestRoute.Create(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{
+		"id": 1,
+		"requester": "alice",
+		"payer": "bob",
+		"amount": 30,
+		"note": "lunch",
+		"status": "pending",
+		"createdAt": "2030-01-01T12:00:00Z",
+		"expiresAt": "2030-01-08T12:00:00Z"
+	}`, w.Body.String())
+
+	mockCoinRequestUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestCoinRequestRoute_Accept_Expired(t *testing.T) {
+	mockCoinRequestUC := new(coinrequestmocks.CoinRequest)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockCoinRequestUC.On("Accept", mock.Anything, "bob", 5).Return(nil, e.ErrExpired)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodPost, "/coinRequests/5/accept", http.NoBody)
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "5"}}
+	c.Set("username", "bob")
+
+	coinRequestRoute := &CoinRequestRoute{
+		coinRequestUC: mockCoinRequestUC,
+		wp:            mockWorkerPool,
+		log:           log,
+	}
+
+	coinRequestRoute.Accept(c)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.JSONEq(t, `{"error":"Coin request expired"}`, w.Body.String())
+
+	mockCoinRequestUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestCoinRequestRoute_Accept_NeedsApproval(t *testing.T) {
+	mockCoinRequestUC := new(coinrequestmocks.CoinRequest)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockCoinRequestUC.On("Accept", mock.Anything, "bob", 5).Return(nil, fmt.Errorf("usecase.coinrequest.Accept: %w", e.ErrApprovalRequired))
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodPost, "/coinRequests/5/accept", http.NoBody)
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "5"}}
+	c.Set("username", "bob")
+
+	coinRequestRoute := &CoinRequestRoute{
+		coinRequestUC: mockCoinRequestUC,
+		wp:            mockWorkerPool,
+		log:           log,
+	}
+
+	coinRequestRoute.Accept(c)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"error":"Payment exceeds the approval threshold, send it as a transfer instead"}`, w.Body.String())
+
+	mockCoinRequestUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestCoinRequestRoute_Decline_AlreadyResolved(t *testing.T) {
+	mockCoinRequestUC := new(coinrequestmocks.CoinRequest)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockCoinRequestUC.On("Decline", mock.Anything, "bob", 5).Return(nil, e.ErrAlreadyResolved)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodPost, "/coinRequests/5/decline", http.NoBody)
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "5"}}
+	c.Set("username", "bob")
+
+	coinRequestRoute := &CoinRequestRoute{
+		coinRequestUC: mockCoinRequestUC,
+		wp:            mockWorkerPool,
+		log:           log,
+	}
+
+	coinRequestRoute.Decline(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":"Coin request already resolved"}`, w.Body.String())
+
+	mockCoinRequestUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
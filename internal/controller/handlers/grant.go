@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	"avito-shop/internal/entity"
+	"avito-shop/internal/usecase/grant"
+	e "avito-shop/pkg/errors"
+	mw "avito-shop/pkg/jwt"
+)
+
+const (
+	// maxBulkGrants bounds the number of rows in an uploaded CSV.
+	maxBulkGrants = 1000
+	// maxGrantReason matches the reason column width.
+	maxGrantReason = 255
+	// maxGrantAmount bounds a CSV row the way GrantRequest binds a single grant.
+	maxGrantAmount = 1000000
+)
+
+type GrantRoute struct {
+	grantUC grant.Grant
+	log     *slog.Logger
+	wp      worker.PoolI
+}
+
+func NewGrantRoute(handler *gin.RouterGroup, grantUC grant.Grant, wp worker.PoolI, log *slog.Logger) {
+	r := &GrantRoute{grantUC, log, wp}
+
+	g := handler.Group("/admin/grants", mw.AuthMW(), mw.AdminMW())
+	{
+		g.POST("", r.Grant)
+		g.POST("/bulk", r.GrantBulk)
+		g.GET("", r.List)
+	}
+}
+
+type GrantRequest struct {
+	Username string `json:"username" binding:"required"`
+	Amount   int    `json:"amount"   binding:"required,ne=0,gte=-1000000,lte=1000000"`
+	Reason   string `json:"reason"   binding:"required,max=255"`
+}
+
+type GrantListQuery struct {
+	Username string `form:"username" binding:"required"`
+}
+
+// Grant mints coins to a user; a negative amount claws them back.
+func (r *GrantRoute) Grant(c *gin.Context) {
+	author, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var req GrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (*entity.CoinGrant, error) {
+		return r.grantUC.Grant(ctx, entity.CoinGrant{
+			Username: req.Username,
+			Amount:   req.Amount,
+			Reason:   req.Reason,
+			Author:   author.(string),
+		})
+	})
+	if err != nil {
+		r.fail(c, "Failed to grant coins", err)
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// GrantBulk applies grants from a CSV file uploaded as the "file" form field.
+// Each row is "username,amount[,reason]"; rows without a reason use the
+// "reason" form field. An optional header row is skipped.
+func (r *GrantRoute) GrantBulk(c *gin.Context) {
+	author, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	grants, err := parseGrantsCSV(c, author.(string))
+	if err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) ([]entity.CoinGrant, error) {
+		return r.grantUC.GrantBulk(ctx, grants)
+	})
+	if err != nil {
+		r.fail(c, "Failed to grant coins", err)
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+func (r *GrantRoute) List(c *gin.Context) {
+	var query GrantListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) ([]entity.CoinGrant, error) {
+		return r.grantUC.List(ctx, query.Username)
+	})
+	if err != nil {
+		r.fail(c, "Failed to list grants", err)
+
+		return
+	}
+
+	if result == nil {
+		result = []entity.CoinGrant{}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (r *GrantRoute) fail(c *gin.Context, failMsg string, err error) {
+	requestLog(c, r.log).Error(failMsg, slog.String("error", err.Error()))
+
+	if abortUnavailable(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, e.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, e.ErrInvalidRequest):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+	case errors.Is(err, e.ErrInsufficientFunds):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient funds"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failMsg})
+	}
+}
+
+func parseGrantsCSV(c *gin.Context, author string) ([]entity.CoinGrant, error) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	defaultReason := c.PostForm("reason")
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var grants []entity.CoinGrant
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if line == 1 && strings.EqualFold(record[0], "username") {
+			continue
+		}
+
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("line %d: expected 2 or 3 fields", line)
+		}
+
+		amount, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil || amount == 0 || amount < -maxGrantAmount || amount > maxGrantAmount {
+			return nil, fmt.Errorf("line %d: invalid amount %q", line, record[1])
+		}
+
+		reason := defaultReason
+		if len(record) == 3 && strings.TrimSpace(record[2]) != "" {
+			reason = strings.TrimSpace(record[2])
+		}
+
+		if reason == "" || len(reason) > maxGrantReason {
+			return nil, fmt.Errorf("line %d: invalid reason", line)
+		}
+
+		grants = append(grants, entity.CoinGrant{
+			Username: strings.TrimSpace(record[0]),
+			Amount:   amount,
+			Reason:   reason,
+			Author:   author,
+		})
+
+		if len(grants) > maxBulkGrants {
+			return nil, fmt.Errorf("more than %d grants", maxBulkGrants)
+		}
+	}
+
+	if len(grants) == 0 {
+		return nil, errors.New("no grants")
+	}
+
+	return grants, nil
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	workermocks "avito-shop/internal/controller/worker/mocks"
+	"avito-shop/internal/entity"
+	grantmocks "avito-shop/internal/usecase/grant/mocks"
+	e "avito-shop/pkg/errors"
+)
+
+func csvUpload(t *testing.T, reason, content string) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	require.NoError(t, writer.WriteField("reason", reason))
+
+	part, err := writer.CreateFormFile("file", "grants.csv")
+	require.NoError(t, err)
+
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return body, writer.FormDataContentType()
+}
+
+func TestGrantRoute_Grant_Success(t *testing.T) {
+	mockGrantUC := new(grantmocks.Grant)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	createdAt := time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC)
+	mockGrantUC.On("Grant", mock.Anything, entity.CoinGrant{
+		Username: "bob",
+		Amount:   -50,
+		Reason:   "refund",
+		Author:   "admin",
+	}).Return(&entity.CoinGrant{
+		ID:        7,
+		Username:  "bob",
+		Amount:    -50,
+		Reason:    "refund",
+		Author:    "admin",
+		CreatedAt: createdAt,
+	}, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"username": "bob", "amount": -50, "reason": "refund"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/grants", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "admin")
+
+	grantRoute := &GrantRoute{
+		grantUC: mockGrantUC,
+		wp:      mockWorkerPool,
+		log:     log,
+	}
+
+	grantRoute.Grant(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{
+		"id": 7,
+		"username": "bob",
+		"amount": -50,
+		"reason": "refund",
+		"author": "admin",
+		"createdAt": "2030-01-01T12:00:00Z"
+	}`, w.Body.String())
+
+	mockGrantUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestGrantRoute_Grant_InsufficientFunds(t *testing.T) {
+	mockGrantUC := new(grantmocks.Grant)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockGrantUC.On("Grant", mock.Anything, mock.Anything).Return(nil, e.ErrInsufficientFunds)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"username": "bob", "amount": -5000, "reason": "mistake"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/grants", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "admin")
+
+	grantRoute := &GrantRoute{
+		grantUC: mockGrantUC,
+		wp:      mockWorkerPool,
+		log:     log,
+	}
+
+	grantRoute.Grant(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "Insufficient funds"}`, w.Body.String())
+
+	mockGrantUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestGrantRoute_GrantBulk_Success(t *testing.T) {
+	mockGrantUC := new(grantmocks.Grant)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	grants := []entity.CoinGrant{
+		{Username: "alice", Amount: 100, Reason: "payroll", Author: "admin"},
+		{Username: "bob", Amount: 250, Reason: "bonus", Author: "admin"},
+	}
+	mockGrantUC.On("GrantBulk", mock.Anything, grants).Return(grants, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	body, contentType := csvUpload(t, "payroll", "username,amount,reason\nalice,100\nbob,250,bonus\n")
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/grants/bulk", body)
+	c.Request.Header.Set("Content-Type", contentType)
+	c.Set("username", "admin")
+
+	grantRoute := &GrantRoute{
+		grantUC: mockGrantUC,
+		wp:      mockWorkerPool,
+		log:     log,
+	}
+
+	grantRoute.GrantBulk(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	mockGrantUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestGrantRoute_GrantBulk_InvalidCSV(t *testing.T) {
+	mockGrantUC := new(grantmocks.Grant)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	body, contentType := csvUpload(t, "payroll", "alice,lots\n")
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/grants/bulk", body)
+	c.Request.Header.Set("Content-Type", contentType)
+	c.Set("username", "admin")
+
+	grantRoute := &GrantRoute{
+		grantUC: mockGrantUC,
+		wp:      mockWorkerPool,
+		log:     log,
+	}
+
+	grantRoute.GrantBulk(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "Invalid request"}`, w.Body.String())
+
+	mockGrantUC.AssertNotCalled(t, "GrantBulk", mock.Anything, mock.Anything)
+	mockWorkerPool.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything)
+}
+
+func TestGrantRoute_GrantBulk_AmountOutOfRange(t *testing.T) {
+	mockGrantUC := new(grantmocks.Grant)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	body, contentType := csvUpload(t, "payroll", "alice,100\nbob,1000001\n")
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/grants/bulk", body)
+	c.Request.Header.Set("Content-Type", contentType)
+	c.Set("username", "admin")
+
+	grantRoute := &GrantRoute{
+		grantUC: mockGrantUC,
+		wp:      mockWorkerPool,
+		log:     log,
+	}
+
+	grantRoute.GrantBulk(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "Invalid request"}`, w.Body.String())
+
+	mockGrantUC.AssertNotCalled(t, "GrantBulk", mock.Anything, mock.Anything)
+	mockWorkerPool.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	"avito-shop/internal/usecase/health"
+	e "avito-shop/pkg/errors"
+)
+
+// readyzTimeout bounds the database checks so a hung Postgres fails the
+// probe instead of outliving it.
+const readyzTimeout = 2 * time.Second
+
+const (
+	checkOK         = "ok"
+	statusOK        = "ok"
+	statusUnhealthy = "unavailable"
+)
+
+type HealthRoute struct {
+	healthUC health.Health
+	pools    *worker.Pools
+	ready    *atomic.Bool
+	log      *slog.Logger
+}
+
+// NewHealthRoute serves the probes. They bypass the worker pools, so they
+// answer even when the pools are saturated.
+func NewHealthRoute(handler *gin.RouterGroup, healthUC health.Health, pools *worker.Pools, ready *atomic.Bool, log *slog.Logger) {
+	r := &HealthRoute{healthUC, pools, ready, log}
+	handler.GET("/healthz", r.Healthz)
+	handler.GET("/readyz", r.Readyz)
+}
+
+type ReadyzResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Healthz answers as long as the process serves HTTP.
+func (r *HealthRoute) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": statusOK})
+}
+
+// Readyz reports whether the instance should get traffic: it is not shutting
+// down, Postgres answers, the schema is at least at the expected migration
+// and no worker pool was shut down. Busy pools stay ready; taking a pod out
+// when it is loaded would only push the load onto the others.
+func (r *HealthRoute) Readyz(c *gin.Context) {
+	if !r.ready.Load() {
+		c.JSON(http.StatusServiceUnavailable, ReadyzResponse{
+			Status: statusUnhealthy,
+			Checks: map[string]string{"shutdown": "in progress"},
+		})
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readyzTimeout)
+	defer cancel()
+
+	checks := map[string]string{
+		"postgres":   checkOK,
+		"migrations": checkOK,
+		"workers":    checkOK,
+	}
+	healthy := true
+	log := requestLog(c, r.log)
+
+	if err := r.healthUC.Ping(ctx); err != nil {
+		log.Error("Readiness check failed", slog.String("check", "postgres"), slog.String("error", err.Error()))
+		checks["postgres"] = "unreachable"
+		healthy = false
+	}
+
+	if err := r.healthUC.CheckMigrations(ctx); err != nil {
+		log.Error("Readiness check failed", slog.String("check", "migrations"), slog.String("error", err.Error()))
+
+		if errors.Is(err, e.ErrSchemaMismatch) {
+			checks["migrations"] = "schema mismatch"
+		} else {
+			checks["migrations"] = "unknown"
+		}
+
+		healthy = false
+	}
+
+	if names := r.pools.Unavailable(); len(names) > 0 {
+		log.Warn("Readiness check failed", slog.String("check", "workers"), slog.Any("pools", names))
+		checks["workers"] = "closed: " + strings.Join(names, ", ")
+		healthy = false
+	}
+
+	if !healthy {
+		c.JSON(http.StatusServiceUnavailable, ReadyzResponse{Status: statusUnhealthy, Checks: checks})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, ReadyzResponse{Status: statusOK, Checks: checks})
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	healthmocks "avito-shop/internal/usecase/health/mocks"
+	e "avito-shop/pkg/errors"
+)
+
+func newTestPools() *worker.Pools {
+	return &worker.Pools{
+		Auth:    worker.NewWorkerPool(1, 1),
+		Writes:  worker.NewWorkerPool(1, 1),
+		Reads:   worker.NewWorkerPool(1, 1),
+		Exports: worker.NewWorkerPool(1, 1),
+	}
+}
+
+func newReady(ready bool) *atomic.Bool {
+	var b atomic.Bool
+	b.Store(ready)
+
+	return &b
+}
+
+func TestHealthRoute_Healthz(t *testing.T) {
+	mockHealthUC := new(healthmocks.Health)
+	pools := newTestPools()
+	defer pools.Shutdown()
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/healthz", http.NoBody)
+
+	healthRoute := &HealthRoute{
+		healthUC: mockHealthUC,
+		pools:    pools,
+		ready:    newReady(false),
+		log:      slog.Default(),
+	}
+
+	healthRoute.Healthz(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status": "ok"}`, w.Body.String())
+}
+
+func TestHealthRoute_Readyz_Ready(t *testing.T) {
+	mockHealthUC := new(healthmocks.Health)
+	pools := newTestPools()
+	defer pools.Shutdown()
+
+	mockHealthUC.On("Ping", mock.Anything).Return(nil)
+	mockHealthUC.On("CheckMigrations", mock.Anything).Return(nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+
+	healthRoute := &HealthRoute{
+		healthUC: mockHealthUC,
+		pools:    pools,
+		ready:    newReady(true),
+		log:      slog.Default(),
+	}
+
+	healthRoute.Readyz(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"status": "ok",
+		"checks": {"postgres": "ok", "migrations": "ok", "workers": "ok"}
+	}`, w.Body.String())
+
+	mockHealthUC.AssertExpectations(t)
+}
+
+func TestHealthRoute_Readyz_SaturatedPool(t *testing.T) {
+	mockHealthUC := new(healthmocks.Health)
+	pools := newTestPools()
+	defer pools.Shutdown()
+
+	// One task keeps the only worker busy and another fills the queue.
+	release := make(chan struct{})
+	defer close(release)
+
+	for range 2 {
+		err := pools.Writes.Submit(context.Background(), func(context.Context) { <-release })
+		assert.NoError(t, err)
+	}
+
+	mockHealthUC.On("Ping", mock.Anything).Return(nil)
+	mockHealthUC.On("CheckMigrations", mock.Anything).Return(nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+
+	healthRoute := &HealthRoute{
+		healthUC: mockHealthUC,
+		pools:    pools,
+		ready:    newReady(true),
+		log:      slog.Default(),
+	}
+
+	healthRoute.Readyz(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHealthRoute_Readyz_Failing(t *testing.T) {
+	mockHealthUC := new(healthmocks.Health)
+	pools := newTestPools()
+	pools.Writes.Shutdown()
+	defer pools.Shutdown()
+
+	mockHealthUC.On("Ping", mock.Anything).Return(errors.New("connection refused"))
+	mockHealthUC.On("CheckMigrations", mock.Anything).
+		Return(fmt.Errorf("usecase.health.CheckMigrations: %w", e.ErrSchemaMismatch))
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+
+	healthRoute := &HealthRoute{
+		healthUC: mockHealthUC,
+		pools:    pools,
+		ready:    newReady(true),
+		log:      slog.Default(),
+	}
+
+	healthRoute.Readyz(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{
+		"status": "unavailable",
+		"checks": {"postgres": "unreachable", "migrations": "schema mismatch", "workers": "closed: writes"}
+	}`, w.Body.String())
+
+	mockHealthUC.AssertExpectations(t)
+}
+
+func TestHealthRoute_Readyz_ShuttingDown(t *testing.T) {
+	mockHealthUC := new(healthmocks.Health)
+	pools := newTestPools()
+	defer pools.Shutdown()
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+
+	healthRoute := &HealthRoute{
+		healthUC: mockHealthUC,
+		pools:    pools,
+		ready:    newReady(false),
+		log:      slog.Default(),
+	}
+
+	healthRoute.Readyz(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status": "unavailable", "checks": {"shutdown": "in progress"}}`, w.Body.String())
+
+	mockHealthUC.AssertNotCalled(t, "Ping", mock.Anything)
+}
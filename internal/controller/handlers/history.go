@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	"avito-shop/internal/entity"
+	"avito-shop/internal/usecase/history"
+	e "avito-shop/pkg/errors"
+	mw "avito-shop/pkg/jwt"
+)
+
+type HistoryRoute struct {
+	historyUC history.History
+	log       *slog.Logger
+	wp        worker.PoolI
+}
+
+func NewHistoryRoute(handler *gin.RouterGroup, historyUC history.History, wp worker.PoolI, log *slog.Logger) {
+	r := &HistoryRoute{historyUC, log, wp}
+	handler.GET("/transactions", mw.AuthMW(), r.List)
+}
+
+type HistoryQuery struct {
+	Cursor       string     `form:"cursor"`
+	Limit        int        `form:"limit"        binding:"omitempty,gt=0,lte=100"`
+	From         *time.Time `form:"from"         time_format:"2006-01-02T15:04:05Z07:00"`
+	To           *time.Time `form:"to"           time_format:"2006-01-02T15:04:05Z07:00"`
+	Counterparty string     `form:"counterparty"`
+	Direction    string     `form:"direction"    binding:"omitempty,oneof=incoming outgoing"`
+}
+
+func (r *HistoryRoute) List(c *gin.Context) {
+	username, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var query HistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	filter := entity.TransactionFilter{
+		Username:     username.(string),
+		Direction:    query.Direction,
+		Counterparty: query.Counterparty,
+		From:         query.From,
+		To:           query.To,
+		Limit:        query.Limit,
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (*entity.TransactionPage, error) {
+		return r.historyUC.List(ctx, filter, query.Cursor)
+	})
+	if err != nil {
+		requestLog(c, r.log).Error("Failed to list transactions", slog.String("error", err.Error()))
+
+		if abortUnavailable(c, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, e.ErrInvalidRequest):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list transactions"})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
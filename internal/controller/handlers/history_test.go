@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	workermocks "avito-shop/internal/controller/worker/mocks"
+	"avito-shop/internal/entity"
+	historymocks "avito-shop/internal/usecase/history/mocks"
+)
+
+func TestHistoryRoute_List_Success(t *testing.T) {
+	mockHistoryUC := new(historymocks.History)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	from := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2030, time.January, 3, 10, 0, 0, 0, time.UTC)
+	mockHistoryUC.On("List", mock.Anything, mock.MatchedBy(func(f entity.TransactionFilter) bool {
+		return f.Username == "alice" &&
+			f.Direction == entity.DirectionOutgoing &&
+			f.Counterparty == "bob" &&
+			f.Limit == 1 &&
+			f.From != nil && f.From.Equal(from) &&
+			f.To == nil
+	}), "MTA").Return(&entity.TransactionPage{
+		Transactions: []entity.CoinTransaction{
+			{
+				ID:        9,
+				FromUser:  "alice",
+				ToUser:    "bob",
+				Amount:    15,
+				CreatedAt: &createdAt,
+			},
+		},
+		NextCursor: "OQ",
+	}, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet,
+		"/transactions?cursor=MTA&limit=1&direction=outgoing&counterparty=bob&from=2030-01-01T00:00:00Z", http.NoBody)
+	c.Set("username", "alice")
+
+	historyRoute := &HistoryRoute{
+		historyUC: mockHistoryUC,
+		wp:        mockWorkerPool,
+		log:       log,
+	}
+
+	historyRoute.List(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"transactions": [
+			{"id": 9, "fromUser": "alice", "toUser": "bob", "amount": 15, "createdAt": "2030-01-03T10:00:00Z"}
+		],
+		"nextCursor": "OQ"
+	}`, w.Body.String())
+
+	mockHistoryUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestHistoryRoute_List_InvalidDirection(t *testing.T) {
+	mockHistoryUC := new(historymocks.History)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/transactions?direction=sideways", http.NoBody)
+	c.Set("username", "alice")
+
+	historyRoute := &HistoryRoute{
+		historyUC: mockHistoryUC,
+		wp:        mockWorkerPool,
+		log:       log,
+	}
+
+	historyRoute.List(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "Invalid request"}`, w.Body.String())
+
+	mockHistoryUC.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+	mockWorkerPool.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/usecase/idempotency"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	jsonContentType          = "application/json; charset=utf-8"
+)
+
+type storedResponse struct {
+	statusCode int
+	body       []byte
+	replayed   bool
+}
+
+func (s storedResponse) write(c *gin.Context) {
+	if s.replayed {
+		c.Header(idempotentReplayedHeader, "true")
+	}
+
+	c.Data(s.statusCode, jsonContentType, s.body)
+}
+
+// idempotencyKey reads the Idempotency-Key header and fingerprints the request
+// so that a reused key with another body can be told apart from a retry.
+func idempotencyKey(c *gin.Context, username string, req any) (entity.IdempotencyKey, bool) {
+	key := c.GetHeader(idempotencyKeyHeader)
+	if len(key) > maxIdempotencyKeyLength {
+		return entity.IdempotencyKey{}, false
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return entity.IdempotencyKey{}, false
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s %s %s", c.Request.Method, c.Request.URL.Path, body)))
+
+	return entity.IdempotencyKey{
+		Username:    username,
+		Key:         key,
+		RequestHash: hex.EncodeToString(sum[:]),
+	}, true
+}
+
+// runIdempotent runs fn directly when no key was sent and through the
+// idempotency use case otherwise.
+func runIdempotent(ctx context.Context,
+	idempotencyUC idempotency.Idempotency,
+	key entity.IdempotencyKey,
+	fn idempotency.Operation,
+) (storedResponse, error) {
+	if key.Key == "" {
+		statusCode, body, err := fn(ctx)
+
+		return storedResponse{statusCode: statusCode, body: body}, err
+	}
+
+	stored, replayed, err := idempotencyUC.Do(ctx, key, fn)
+	if err != nil {
+		return storedResponse{}, err
+	}
+
+	return storedResponse{statusCode: stored.StatusCode, body: stored.Response, replayed: replayed}, nil
+}
+
+func jsonResponse(statusCode int, obj any) (int, []byte, error) {
+	body, err := json.Marshal(obj)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return statusCode, body, nil
+}
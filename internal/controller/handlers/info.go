@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -14,6 +15,8 @@ import (
 	mw "avito-shop/pkg/jwt"
 )
 
+const historyAggregated = "aggregated"
+
 type InfoRoute struct {
 	infoUC info.Info
 	log    *slog.Logger
@@ -26,40 +29,53 @@ func NewInfoRoute(handler *gin.RouterGroup, infoUC info.Info, wp worker.PoolI, l
 }
 
 func (r *InfoRoute) Info(c *gin.Context) {
-	resultChan := make(chan interface{}, 1)
-	errorChan := make(chan error, 1)
-
 	username, exists := c.Get("username")
 	if !exists {
-		r.log.Error("Username not found in context")
+		requestLog(c, r.log).Error("Username not found in context")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 
 		return
 	}
 
-	r.wp.Submit(func() {
-		information, err := r.infoUC.GetInfo(c.Request.Context(), username.(string))
-		if err != nil {
-			errorChan <- err
-			return
+	var query InfoQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (interface{}, error) {
+		if query.History == historyAggregated {
+			return r.infoUC.GetAggregatedInfo(ctx, username.(string))
 		}
 
-		resultChan <- information
+		return r.infoUC.GetInfo(ctx, username.(string))
 	})
+	if err != nil {
+		requestLog(c, r.log).Error("Failed to get info", slog.String("error", err.Error()))
 
-	select {
-	case result := <-resultChan:
-		c.JSON(http.StatusOK, result)
-	case err := <-errorChan:
-		r.log.Error("Failed to get info", slog.String("error", err.Error()))
+		if abortUnavailable(c, err) {
+			return
+		}
 
 		switch {
 		case errors.Is(err, e.ErrInvalidCredentials), errors.Is(err, e.ErrNotFound):
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credentials"})
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get info"})
 		}
+
+		return
 	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// InfoQuery selects the history mode: the latest rows (default) or totals
+// per counterparty.
+type InfoQuery struct {
+	History string `form:"history" binding:"omitempty,oneof=rows aggregated"`
 }
 
 type InfoResponse struct {
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -22,10 +24,12 @@ func TestInfoRoute_Info_Success(t *testing.T) {
 	mockWorkerPool := new(workermocks.PoolI)
 	log := slog.Default()
 
-	mockWorkerPool.On("Submit", mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
-		task := args.Get(0).(worker.Task)
-		task()
-	}).Return()
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	receivedAt := time.Date(2030, time.January, 2, 9, 0, 0, 0, time.UTC)
 
 	expectedInfo := &entity.Info{
 		Coins: 100,
@@ -35,10 +39,11 @@ func TestInfoRoute_Info_Success(t *testing.T) {
 		},
 		CoinHistory: entity.CoinHistory{
 			Received: []entity.ReceivedTransaction{
-				{FromUser: "user1", Amount: 50},
+				{ID: 2, FromUser: "user1", Amount: 50, CreatedAt: &receivedAt},
 			},
 			Sent: []entity.SentTransaction{
-				{ToUser: "user2", Amount: 30},
+				// sent before transfer times were recorded
+				{ID: 1, ToUser: "user2", Amount: 30},
 			},
 		},
 	}
@@ -70,10 +75,10 @@ func TestInfoRoute_Info_Success(t *testing.T) {
 		],
 		"coinHistory": {
 			"received": [
-				{"fromUser": "user1", "amount": 50}
+				{"id": 2, "fromUser": "user1", "amount": 50, "createdAt": "2030-01-02T09:00:00Z"}
 			],
 			"sent": [
-				{"toUser": "user2", "amount": 30}
+				{"id": 1, "toUser": "user2", "amount": 30}
 			]
 		}
 	}`, w.Body.String())
@@ -82,6 +87,143 @@ func TestInfoRoute_Info_Success(t *testing.T) {
 	mockWorkerPool.AssertExpectations(t)
 }
 
+func TestInfoRoute_Info_WithExpiredCoins(t *testing.T) {
+	mockInfoUC := new(infomocks.Info)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	expectedInfo := &entity.Info{
+		Coins:     0,
+		Inventory: []entity.InventoryItem{},
+		CoinHistory: entity.CoinHistory{
+			Received: []entity.ReceivedTransaction{},
+			Sent:     []entity.SentTransaction{},
+			Expired: []entity.ExpiredCoins{
+				{Amount: 1000, ExpiredAt: time.Date(2031, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+	mockInfoUC.On("GetInfo", mock.Anything, "testuser").Return(expectedInfo, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/info", http.NoBody)
+	c.Set("username", "testuser")
+
+	infoRoute := &InfoRoute{
+		infoUC: mockInfoUC,
+		wp:     mockWorkerPool,
+		log:    log,
+	}
+
+	infoRoute.Info(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"coins": 0,
+		"inventory": [],
+		"coinHistory": {
+			"received": [],
+			"sent": [],
+			"expired": [
+				{"amount": 1000, "expiredAt": "2031-01-01T00:00:00Z"}
+			]
+		}
+	}`, w.Body.String())
+
+	mockInfoUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestInfoRoute_Info_Aggregated(t *testing.T) {
+	mockInfoUC := new(infomocks.Info)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	expectedInfo := &entity.AggregatedInfo{
+		Coins:     100,
+		Inventory: []entity.InventoryItem{},
+		CoinHistory: entity.AggregatedCoinHistory{
+			Received: []entity.ReceivedSummary{
+				{FromUser: "user1", Amount: 80, Count: 3},
+			},
+			Sent: []entity.SentSummary{
+				{ToUser: "user2", Amount: 30, Count: 1},
+			},
+			Expired: entity.ExpirySummary{Amount: 50, Count: 2},
+		},
+	}
+	mockInfoUC.On("GetAggregatedInfo", mock.Anything, "testuser").Return(expectedInfo, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/info?history=aggregated", http.NoBody)
+	c.Set("username", "testuser")
+
+	infoRoute := &InfoRoute{
+		infoUC: mockInfoUC,
+		wp:     mockWorkerPool,
+		log:    log,
+	}
+
+	infoRoute.Info(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"coins": 100,
+		"inventory": [],
+		"coinHistory": {
+			"received": [{"fromUser": "user1", "amount": 80, "count": 3}],
+			"sent": [{"toUser": "user2", "amount": 30, "count": 1}],
+			"expired": {"amount": 50, "count": 2}
+		}
+	}`, w.Body.String())
+
+	mockInfoUC.AssertNotCalled(t, "GetInfo", mock.Anything, mock.Anything)
+}
+
+func TestInfoRoute_Info_InvalidHistoryMode(t *testing.T) {
+	mockInfoUC := new(infomocks.Info)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/info?history=monthly", http.NoBody)
+	c.Set("username", "testuser")
+
+	infoRoute := &InfoRoute{
+		infoUC: mockInfoUC,
+		wp:     mockWorkerPool,
+		log:    log,
+	}
+
+	infoRoute.Info(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "Invalid request"}`, w.Body.String())
+	mockWorkerPool.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything)
+}
+
 func TestInfoRoute_Info_Unauthorized(t *testing.T) {
 	mockInfoUC := new(infomocks.Info)
 	mockWorkerPool := new(workermocks.PoolI)
@@ -111,10 +253,10 @@ func TestInfoRoute_Info_InternalError(t *testing.T) {
 	mockWorkerPool := new(workermocks.PoolI)
 	log := slog.Default()
 
-	mockWorkerPool.On("Submit", mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
-		task := args.Get(0).(worker.Task)
-		task()
-	}).Return()
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
 
 	mockInfoUC.On("GetInfo", mock.Anything, "testuser").Return(nil, errors.New("internal error"))
 
@@ -140,3 +282,37 @@ func TestInfoRoute_Info_InternalError(t *testing.T) {
 	mockInfoUC.AssertExpectations(t)
 	mockWorkerPool.AssertExpectations(t)
 }
+
+func TestInfoRoute_Info_UseCasePanics(t *testing.T) {
+	mockInfoUC := new(infomocks.Info)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockInfoUC.On("GetInfo", mock.Anything, "testuser").Run(func(mock.Arguments) {
+		panic("boom")
+	}).Return(nil, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/info", http.NoBody)
+	c.Set("username", "testuser")
+
+	infoRoute := &InfoRoute{
+		infoUC: mockInfoUC,
+		wp:     mockWorkerPool,
+		log:    log,
+	}
+
+	infoRoute.Info(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":"Failed to get info"}`, w.Body.String())
+}
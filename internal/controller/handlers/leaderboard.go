@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	"avito-shop/internal/entity"
+	"avito-shop/internal/usecase/analytics"
+	e "avito-shop/pkg/errors"
+	mw "avito-shop/pkg/jwt"
+)
+
+type LeaderboardRoute struct {
+	analyticsUC analytics.Analytics
+	log         *slog.Logger
+	wp          worker.PoolI
+}
+
+func NewLeaderboardRoute(handler *gin.RouterGroup, analyticsUC analytics.Analytics, wp worker.PoolI, log *slog.Logger) {
+	r := &LeaderboardRoute{analyticsUC, log, wp}
+
+	leaderboards := handler.Group("/leaderboards", mw.AuthMW())
+	leaderboards.GET("", r.Get)
+	leaderboards.PUT("/optOut", r.SetOptOut)
+}
+
+type LeaderboardQuery struct {
+	Window string `form:"window" binding:"omitempty,oneof=week month all"`
+}
+
+type OptOutRequest struct {
+	OptOut *bool `json:"optOut" binding:"required"`
+}
+
+func (r *LeaderboardRoute) Get(c *gin.Context) {
+	if _, exists := c.Get("username"); !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var query LeaderboardQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	if query.Window == "" {
+		query.Window = entity.LeaderboardWindowMonth
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (*entity.Leaderboard, error) {
+		return r.analyticsUC.Leaderboard(ctx, query.Window)
+	})
+	if err != nil {
+		requestLog(c, r.log).Error("Failed to get leaderboard", slog.String("error", err.Error()))
+
+		if abortUnavailable(c, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, e.ErrInvalidRequest):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get leaderboard"})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (r *LeaderboardRoute) SetOptOut(c *gin.Context) {
+	username, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var req OptOutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	_, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, r.analyticsUC.SetOptOut(ctx, username.(string), *req.OptOut)
+	})
+	if err != nil {
+		requestLog(c, r.log).Error("Failed to update leaderboard opt-out", slog.String("error", err.Error()))
+
+		if abortUnavailable(c, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, e.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update leaderboard opt-out"})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"optOut": *req.OptOut})
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	workermocks "avito-shop/internal/controller/worker/mocks"
+	"avito-shop/internal/entity"
+	analyticsmocks "avito-shop/internal/usecase/analytics/mocks"
+)
+
+func newLeaderboardRoute(uc *analyticsmocks.Analytics) *LeaderboardRoute {
+	mockWorkerPool := new(workermocks.PoolI)
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	return &LeaderboardRoute{
+		analyticsUC: uc,
+		wp:          mockWorkerPool,
+		log:         slog.Default(),
+	}
+}
+
+func TestLeaderboardRoute_Get_DefaultsToMonth(t *testing.T) {
+	mockAnalyticsUC := new(analyticsmocks.Analytics)
+
+	since := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	mockAnalyticsUC.On("Leaderboard", mock.Anything, entity.LeaderboardWindowMonth).Return(&entity.Leaderboard{
+		Window:       entity.LeaderboardWindowMonth,
+		Since:        &since,
+		RefreshedAt:  time.Date(2030, time.January, 5, 12, 0, 0, 0, time.UTC),
+		TopReceivers: []entity.UserScore{{Username: "bob", Coins: 120, Count: 4}},
+		TopSenders:   []entity.UserScore{{Username: "alice", Coins: 90, Count: 3}},
+		MostGenerous: []entity.UserScore{{Username: "alice", Coins: 90, Count: 2}},
+		MostBought:   []entity.ItemScore{{Item: "cup", Count: 7}},
+	}, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/leaderboards", http.NoBody)
+	c.Set("username", "alice")
+
+	newLeaderboardRoute(mockAnalyticsUC).Get(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"window": "month",
+		"since": "2030-01-01T00:00:00Z",
+		"refreshedAt": "2030-01-05T12:00:00Z",
+		"topReceivers": [{"username": "bob", "coins": 120, "count": 4}],
+		"topSenders": [{"username": "alice", "coins": 90, "count": 3}],
+		"mostGenerous": [{"username": "alice", "coins": 90, "count": 2}],
+		"mostBought": [{"item": "cup", "count": 7}]
+	}`, w.Body.String())
+}
+
+func TestLeaderboardRoute_Get_InvalidWindow(t *testing.T) {
+	mockAnalyticsUC := new(analyticsmocks.Analytics)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/leaderboards?window=decade", http.NoBody)
+	c.Set("username", "alice")
+
+	newLeaderboardRoute(mockAnalyticsUC).Get(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "Invalid request"}`, w.Body.String())
+	mockAnalyticsUC.AssertNotCalled(t, "Leaderboard", mock.Anything, mock.Anything)
+}
+
+func TestLeaderboardRoute_SetOptOut_Success(t *testing.T) {
+	mockAnalyticsUC := new(analyticsmocks.Analytics)
+	mockAnalyticsUC.On("SetOptOut", mock.Anything, "alice", false).Return(nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodPut, "/leaderboards/optOut", strings.NewReader(`{"optOut": false}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "alice")
+
+	newLeaderboardRoute(mockAnalyticsUC).SetOptOut(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"optOut": false}`, w.Body.String())
+	mockAnalyticsUC.AssertExpectations(t)
+}
+
+func TestLeaderboardRoute_SetOptOut_MissingFlag(t *testing.T) {
+	mockAnalyticsUC := new(analyticsmocks.Analytics)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodPut, "/leaderboards/optOut", strings.NewReader(`{}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "alice")
+
+	newLeaderboardRoute(mockAnalyticsUC).SetOptOut(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockAnalyticsUC.AssertNotCalled(t, "SetOptOut", mock.Anything, mock.Anything, mock.Anything)
+}
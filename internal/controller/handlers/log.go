@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/pkg/logger"
+)
+
+// requestLog returns the request-scoped logger set up by logger.RequestID,
+// which carries the request ID and username, or log outside of a request.
+func requestLog(c *gin.Context, log *slog.Logger) *slog.Logger {
+	if c.Request == nil {
+		return log
+	}
+
+	return logger.FromContext(c.Request.Context(), log)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+
+	"avito-shop/internal/controller/worker"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
+)
+
+const (
+	// retryAfter is the Retry-After hint, in seconds, sent when the pool is saturated.
+	retryAfter = 1
+	// statusClientClosedRequest is logged for requests abandoned by the client.
+	statusClientClosedRequest = 499
+)
+
+// submitAndWait runs fn on the worker pool and waits for its result or for
+// ctx to be done. A task picked up after ctx is done is skipped, and a panic
+// in fn is returned as a *worker.PanicError. fn gets the task's ctx, so its
+// spans nest under the worker's; its error is recorded there.
+func submitAndWait[T any](ctx context.Context, wp worker.PoolI, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+
+	resultChan := make(chan T, 1)
+	errorChan := make(chan error, 1)
+
+	err := wp.Submit(ctx, func(ctx context.Context) {
+		if err := ctx.Err(); err != nil {
+			errorChan <- err
+
+			return
+		}
+
+		var result T
+
+		err := worker.Catch(func() (err error) {
+			result, err = fn(ctx)
+
+			return err
+		})
+		if err != nil {
+			tracing.RecordError(trace.SpanFromContext(ctx), err)
+			errorChan <- err
+
+			return
+		}
+
+		resultChan <- result
+	})
+	if err != nil {
+		return zero, err
+	}
+
+	select {
+	case result := <-resultChan:
+		return result, nil
+	case err := <-errorChan:
+		return zero, err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// abortUnavailable answers errors that come from the pool or the request
+// rather than the use case and reports whether it did: 503 with Retry-After
+// when the pool is saturated or shutting down, a bare 499 when the client
+// has gone away and 503 when the request deadline passed.
+func abortUnavailable(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, e.ErrPoolSaturated), errors.Is(err, e.ErrPoolClosed):
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Service is busy, retry later"})
+	case errors.Is(err, context.Canceled):
+		c.AbortWithStatus(statusClientClosedRequest)
+	case errors.Is(err, context.DeadlineExceeded):
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Request timed out"})
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	"avito-shop/internal/entity"
+	"avito-shop/internal/usecase/reversal"
+	e "avito-shop/pkg/errors"
+	mw "avito-shop/pkg/jwt"
+)
+
+type ReversalRoute struct {
+	reversalUC reversal.Reversal
+	log        *slog.Logger
+	wp         worker.PoolI
+}
+
+func NewReversalRoute(handler *gin.RouterGroup, reversalUC reversal.Reversal, wp worker.PoolI, log *slog.Logger) {
+	r := &ReversalRoute{reversalUC, log, wp}
+	handler.POST("/admin/transactions/:id/reverse", mw.AuthMW(), mw.AdminMW(), r.Reverse)
+}
+
+type ReversalURI struct {
+	ID int `uri:"id" binding:"required,gt=0"`
+}
+
+type ReversalRequest struct {
+	Reason       string `json:"reason"       binding:"required,max=150"`
+	AllowPartial bool   `json:"allowPartial"`
+}
+
+func (r *ReversalRoute) Reverse(c *gin.Context) {
+	admin, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var uri ReversalURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	var req ReversalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (*entity.Reversal, error) {
+		return r.reversalUC.Reverse(ctx, admin.(string), uri.ID, req.Reason, req.AllowPartial)
+	})
+	if err != nil {
+		requestLog(c, r.log).Error("Failed to reverse transaction", slog.String("error", err.Error()))
+
+		if abortUnavailable(c, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, e.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+		case errors.Is(err, e.ErrInvalidRequest):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Reversals cannot be reversed"})
+		case errors.Is(err, e.ErrInsufficientFunds):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Recipient has insufficient funds"})
+		case errors.Is(err, e.ErrAlreadyResolved):
+			c.JSON(http.StatusConflict, gin.H{"error": "Transaction already reversed"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reverse transaction"})
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
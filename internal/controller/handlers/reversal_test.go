@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	workermocks "avito-shop/internal/controller/worker/mocks"
+	"avito-shop/internal/entity"
+	reversalmocks "avito-shop/internal/usecase/reversal/mocks"
+	e "avito-shop/pkg/errors"
+)
+
+func TestReversalRoute_Reverse_Partial(t *testing.T) {
+	mockReversalUC := new(reversalmocks.Reversal)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockReversalUC.On("Reverse", mock.Anything, "admin", 12, "fraud", true).Return(&entity.Reversal{
+		TransactionID: 12,
+		ReversalID:    40,
+		Amount:        60,
+		Shortfall:     40,
+		Reason:        "fraud",
+		ReversedBy:    "admin",
+	}, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"reason": "fraud", "allowPartial": true}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/transactions/12/reverse", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "12"}}
+	c.Set("username", "admin")
+
+	reversalRoute := &ReversalRoute{
+		reversalUC: mockReversalUC,
+		wp:         mockWorkerPool,
+		log:        log,
+	}
+
+	reversalRoute.Reverse(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"transactionId": 12,
+		"reversalId": 40,
+		"amount": 60,
+		"shortfall": 40,
+		"reason": "fraud",
+		"reversedBy": "admin"
+	}`, w.Body.String())
+
+	mockReversalUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestReversalRoute_Reverse_AlreadyReversed(t *testing.T) {
+	mockReversalUC := new(reversalmocks.Reversal)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockReversalUC.On("Reverse", mock.Anything, "admin", 12, "mistake", false).Return(nil, e.ErrAlreadyResolved)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"reason": "mistake"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/transactions/12/reverse", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "12"}}
+	c.Set("username", "admin")
+
+	reversalRoute := &ReversalRoute{
+		reversalUC: mockReversalUC,
+		wp:         mockWorkerPool,
+		log:        log,
+	}
+
+	reversalRoute.Reverse(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error": "Transaction already reversed"}`, w.Body.String())
+
+	mockReversalUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
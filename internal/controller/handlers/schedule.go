@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	"avito-shop/internal/entity"
+	"avito-shop/internal/usecase/schedule"
+	e "avito-shop/pkg/errors"
+	mw "avito-shop/pkg/jwt"
+)
+
+type ScheduleRoute struct {
+	scheduleUC schedule.Schedule
+	log        *slog.Logger
+	wp         worker.PoolI
+}
+
+func NewScheduleRoute(handler *gin.RouterGroup, scheduleUC schedule.Schedule, wp worker.PoolI, log *slog.Logger) {
+	r := &ScheduleRoute{scheduleUC, log, wp}
+
+	g := handler.Group("/scheduledTransfers", mw.AuthMW())
+	{
+		g.POST("", r.Create)
+		g.GET("", r.List)
+		g.GET("/:id", r.Get)
+		g.PUT("/:id", r.Update)
+		g.DELETE("/:id", r.Delete)
+	}
+}
+
+type ScheduleRequest struct {
+	ToUser    string    `json:"toUser"    binding:"required"`
+	Amount    int       `json:"amount"    binding:"required,gt=0,lte=1000000"`
+	Message   string    `json:"message"   binding:"omitempty,max=200"`
+	Repeat    string    `json:"repeat"    binding:"omitempty,oneof=daily weekly monthly"`
+	NextRunAt time.Time `json:"nextRunAt" binding:"required"`
+}
+
+type ScheduleURI struct {
+	ID int `uri:"id" binding:"required,gt=0"`
+}
+
+func (req ScheduleRequest) toEntity(username string) entity.ScheduledTransfer {
+	return entity.ScheduledTransfer{
+		FromUser:  username,
+		ToUser:    req.ToUser,
+		Amount:    req.Amount,
+		Message:   req.Message,
+		Repeat:    req.Repeat,
+		NextRunAt: req.NextRunAt,
+	}
+}
+
+func (r *ScheduleRoute) Create(c *gin.Context) {
+	username, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	r.run(c, http.StatusCreated, "Failed to schedule transfer", func(ctx context.Context) (any, error) {
+		return r.scheduleUC.Create(ctx, req.toEntity(username.(string)))
+	})
+}
+
+func (r *ScheduleRoute) List(c *gin.Context) {
+	username, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	r.run(c, http.StatusOK, "Failed to list scheduled transfers", func(ctx context.Context) (any, error) {
+		transfers, err := r.scheduleUC.List(ctx, username.(string))
+		if err != nil {
+			return nil, err
+		}
+
+		if transfers == nil {
+			transfers = []entity.ScheduledTransfer{}
+		}
+
+		return transfers, nil
+	})
+}
+
+func (r *ScheduleRoute) Get(c *gin.Context) {
+	username, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var uri ScheduleURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	r.run(c, http.StatusOK, "Failed to get scheduled transfer", func(ctx context.Context) (any, error) {
+		return r.scheduleUC.Get(ctx, username.(string), uri.ID)
+	})
+}
+
+func (r *ScheduleRoute) Update(c *gin.Context) {
+	username, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var uri ScheduleURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	st := req.toEntity(username.(string))
+	st.ID = uri.ID
+
+	r.run(c, http.StatusOK, "Failed to update scheduled transfer", func(ctx context.Context) (any, error) {
+		return r.scheduleUC.Update(ctx, st)
+	})
+}
+
+func (r *ScheduleRoute) Delete(c *gin.Context) {
+	username, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var uri ScheduleURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	r.run(c, http.StatusOK, "Failed to delete scheduled transfer", func(ctx context.Context) (any, error) {
+		if err := r.scheduleUC.Delete(ctx, username.(string), uri.ID); err != nil {
+			return nil, err
+		}
+
+		return "Scheduled transfer deleted", nil
+	})
+}
+
+// run executes fn on the worker pool and writes its result or a mapped error.
+func (r *ScheduleRoute) run(c *gin.Context, status int, failMsg string, fn func(ctx context.Context) (any, error)) {
+	result, err := submitAndWait(c.Request.Context(), r.wp, fn)
+	if err == nil {
+		c.JSON(status, result)
+
+		return
+	}
+
+	requestLog(c, r.log).Error(failMsg, slog.String("error", err.Error()))
+
+	if abortUnavailable(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, e.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduled transfer or recipient not found"})
+	case errors.Is(err, e.ErrInvalidRequest):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failMsg})
+	}
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	workermocks "avito-shop/internal/controller/worker/mocks"
+	"avito-shop/internal/entity"
+	schedulemocks "avito-shop/internal/usecase/schedule/mocks"
+	e "avito-shop/pkg/errors"
+)
+
+func TestScheduleRoute_Create_Success(t *testing.T) {
+	mockScheduleUC := new(schedulemocks.Schedule)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	nextRunAt := time.Date(2030, time.January, 6, 10, 0, 0, 0, time.UTC)
+	in := entity.ScheduledTransfer{
+		FromUser:  "lead",
+		ToUser:    "intern",
+		Amount:    50,
+		Repeat:    entity.RepeatWeekly,
+		NextRunAt: nextRunAt,
+	}
+	created := in
+	created.ID = 1
+	created.Active = true
+
+	mockScheduleUC.On("Create", mock.Anything, in).Return(&created, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := fmt.Sprintf(`{"toUser": "intern", "amount": 50, "repeat": "weekly", "nextRunAt": %q}`,
+		nextRunAt.Format(time.RFC3339))
+	c.Request = httptest.NewRequest(http.MethodPost, "/scheduledTransfers", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "lead")
+
+	scheduleRoute := &ScheduleRoute{
+		scheduleUC: mockScheduleUC,
+		wp:         mockWorkerPool,
+		log:        log,
+	}
+
+	scheduleRoute.Create(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{
+		"id": 1,
+		"fromUser": "lead",
+		"toUser": "intern",
+		"amount": 50,
+		"repeat": "weekly",
+		"nextRunAt": "2030-01-06T10:00:00Z",
+		"active": true,
+		"failures": 0
+	}`, w.Body.String())
+
+	mockScheduleUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestScheduleRoute_Create_InvalidRepeat(t *testing.T) {
+	mockScheduleUC := new(schedulemocks.Schedule)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"toUser": "intern", "amount": 50, "repeat": "hourly", "nextRunAt": "2030-01-06T10:00:00Z"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/scheduledTransfers", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "lead")
+
+	scheduleRoute := &ScheduleRoute{
+		scheduleUC: mockScheduleUC,
+		wp:         mockWorkerPool,
+		log:        log,
+	}
+
+	scheduleRoute.Create(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"Invalid request"}`, w.Body.String())
+}
+
+func TestScheduleRoute_Delete_NotFound(t *testing.T) {
+	mockScheduleUC := new(schedulemocks.Schedule)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockScheduleUC.On("Delete", mock.Anything, "lead", 7).Return(e.ErrNotFound)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodDelete, "/scheduledTransfers/7", http.NoBody)
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "7"}}
+	c.Set("username", "lead")
+
+	scheduleRoute := &ScheduleRoute{
+		scheduleUC: mockScheduleUC,
+		wp:         mockWorkerPool,
+		log:        log,
+	}
+
+	scheduleRoute.Delete(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"Scheduled transfer or recipient not found"}`, w.Body.String())
+
+	mockScheduleUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
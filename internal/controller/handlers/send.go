@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -8,34 +9,50 @@ import (
 	"golang.org/x/exp/slog"
 
 	"avito-shop/internal/controller/worker"
+	"avito-shop/internal/entity"
+	"avito-shop/internal/usecase/idempotency"
 	"avito-shop/internal/usecase/send"
 	e "avito-shop/pkg/errors"
 	mw "avito-shop/pkg/jwt"
 )
 
 type SendRoute struct {
-	sendUC send.Send
-	log    *slog.Logger
-	wp     worker.PoolI
+	sendUC        send.Send
+	idempotencyUC idempotency.Idempotency
+	log           *slog.Logger
+	wp            worker.PoolI
 }
 
-func NewSendRoute(handler *gin.RouterGroup, sendUC send.Send, wp worker.PoolI, log *slog.Logger) {
-	r := &SendRoute{sendUC, log, wp}
+func NewSendRoute(handler *gin.RouterGroup,
+	sendUC send.Send,
+	idempotencyUC idempotency.Idempotency,
+	wp worker.PoolI,
+	log *slog.Logger,
+) {
+	r := &SendRoute{sendUC, idempotencyUC, log, wp}
 	handler.POST("/sendCoin", mw.AuthMW(), r.Send)
+	handler.POST("/sendCoin/batch", mw.AuthMW(), r.SendBatch)
 }
 
 type SendRequest struct {
+	ToUser  string `json:"toUser" binding:"required"`
+	Amount  int    `json:"amount" binding:"required,gt=0,lte=1000000"`
+	Message string `json:"message" binding:"omitempty,max=200"`
+}
+
+type SendBatchRequest struct {
+	Transfers []TransferRequest `json:"transfers" binding:"required,min=1,max=100,dive"`
+}
+
+type TransferRequest struct {
 	ToUser string `json:"toUser" binding:"required"`
 	Amount int    `json:"amount" binding:"required,gt=0,lte=1000000"`
 }
 
 func (r *SendRoute) Send(c *gin.Context) {
-	resultChan := make(chan string, 1)
-	errorChan := make(chan error, 1)
-
 	username, exists := c.Get("username")
 	if !exists {
-		r.log.Error("Username not found in context")
+		requestLog(c, r.log).Error("Username not found in context")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 
 		return
@@ -43,34 +60,121 @@ func (r *SendRoute) Send(c *gin.Context) {
 
 	var req SendRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		r.log.Error("Failed to parse request", slog.String("error", err.Error()))
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 
 		return
 	}
 
-	r.wp.Submit(func() {
-		err := r.sendUC.SendCoin(c.Request.Context(), username.(string), req.ToUser, req.Amount)
-		if err != nil {
-			errorChan <- err
+	key, ok := idempotencyKey(c, username.(string), req)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid idempotency key"})
+
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (storedResponse, error) {
+		return runIdempotent(ctx, r.idempotencyUC, key, func(ctx context.Context) (int, []byte, error) {
+			pending, err := r.sendUC.SendCoin(ctx, username.(string), req.ToUser, req.Amount, req.Message)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			if pending != nil {
+				return jsonResponse(http.StatusAccepted, pending)
+			}
+
+			return jsonResponse(http.StatusOK, "Coins sent successfully")
+		})
+	})
+	if err != nil {
+		requestLog(c, r.log).Error("Failed to send coins", slog.String("error", err.Error()))
 
+		if abortUnavailable(c, err) {
 			return
 		}
 
-		resultChan <- "Coins sent successfully"
+		switch {
+		case errors.Is(err, e.ErrInsufficientFunds):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient funds"})
+		case errors.Is(err, e.ErrInvalidCredentials), errors.Is(err, e.ErrNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credentials"})
+		case errors.Is(err, e.ErrIdempotencyKeyReused):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency key reused with different request"})
+		case errors.Is(err, e.ErrLimitExceeded):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Send limit exceeded"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send coins"})
+		}
+
+		return
+	}
+
+	result.write(c)
+}
+
+func (r *SendRoute) SendBatch(c *gin.Context) {
+	username, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var req SendBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	key, ok := idempotencyKey(c, username.(string), req)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid idempotency key"})
+
+		return
+	}
+
+	transfers := make([]entity.Transfer, 0, len(req.Transfers))
+	for _, t := range req.Transfers {
+		transfers = append(transfers, entity.Transfer{ToUser: t.ToUser, Amount: t.Amount})
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (storedResponse, error) {
+		return runIdempotent(ctx, r.idempotencyUC, key, func(ctx context.Context) (int, []byte, error) {
+			if err := r.sendUC.SendCoinBatch(ctx, username.(string), transfers); err != nil {
+				return 0, nil, err
+			}
+
+			return jsonResponse(http.StatusOK, "Coins sent successfully")
+		})
 	})
+	if err != nil {
+		requestLog(c, r.log).Error("Failed to send coins", slog.String("error", err.Error()))
 
-	select {
-	case result := <-resultChan:
-		c.JSON(http.StatusOK, result)
-	case err := <-errorChan:
-		r.log.Error("Failed to send coins", slog.String("error", err.Error()))
+		if abortUnavailable(c, err) {
+			return
+		}
 
 		switch {
+		case errors.Is(err, e.ErrInsufficientFunds):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Insufficient funds"})
 		case errors.Is(err, e.ErrInvalidCredentials), errors.Is(err, e.ErrNotFound):
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credentials"})
+		case errors.Is(err, e.ErrIdempotencyKeyReused):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency key reused with different request"})
+		case errors.Is(err, e.ErrLimitExceeded):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Send limit exceeded"})
+		case errors.Is(err, e.ErrApprovalRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Transfers above the approval threshold must be sent one by one"})
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send coins"})
 		}
+
+		return
 	}
+
+	result.write(c)
 }
@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -14,7 +16,10 @@ import (
 
 	"avito-shop/internal/controller/worker"
 	workermocks "avito-shop/internal/controller/worker/mocks"
+	"avito-shop/internal/entity"
+	idempotencymocks "avito-shop/internal/usecase/idempotency/mocks"
 	sendmocks "avito-shop/internal/usecase/send/mocks"
+	e "avito-shop/pkg/errors"
 )
 
 func TestSendRoute_Send_Success(t *testing.T) {
@@ -22,12 +27,12 @@ func TestSendRoute_Send_Success(t *testing.T) {
 	mockWorkerPool := new(workermocks.PoolI)
 	log := slog.Default()
 
-	mockWorkerPool.On("Submit", mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
-		task := args.Get(0).(worker.Task)
-		task()
-	}).Return()
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
 
-	mockSendUC.On("SendCoin", mock.Anything, "senderUser", "receiverUser", 100).Return(nil)
+	mockSendUC.On("SendCoin", mock.Anything, "senderUser", "receiverUser", 100, "").Return(nil, nil)
 
 	gin.SetMode(gin.TestMode)
 
@@ -112,12 +117,12 @@ func TestSendRoute_Send_InternalError(t *testing.T) {
 	mockWorkerPool := new(workermocks.PoolI)
 	log := slog.Default()
 
-	mockWorkerPool.On("Submit", mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
-		task := args.Get(0).(worker.Task)
-		task()
-	}).Return()
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
 
-	mockSendUC.On("SendCoin", mock.Anything, "senderUser", "receiverUser", 100).Return(errors.New("internal error"))
+	mockSendUC.On("SendCoin", mock.Anything, "senderUser", "receiverUser", 100, "").Return(nil, errors.New("internal error"))
 
 	gin.SetMode(gin.TestMode)
 
@@ -143,3 +148,309 @@ func TestSendRoute_Send_InternalError(t *testing.T) {
 	mockSendUC.AssertExpectations(t)
 	mockWorkerPool.AssertExpectations(t)
 }
+
+func TestSendRoute_Send_IdempotentReplay(t *testing.T) {
+	mockSendUC := new(sendmocks.Send)
+	mockIdempotencyUC := new(idempotencymocks.Idempotency)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockIdempotencyUC.On("Do", mock.Anything, mock.MatchedBy(func(key entity.IdempotencyKey) bool {
+		return key.Username == "senderUser" && key.Key == "key-1" && key.RequestHash != ""
+	}), mock.Anything).Return(&entity.IdempotencyKey{
+		StatusCode: http.StatusOK,
+		Response:   []byte(`"Coins sent successfully"`),
+	}, true, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"toUser": "receiverUser", "amount": 100}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/sendCoin", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("Idempotency-Key", "key-1")
+	c.Set("username", "senderUser")
+
+	sendRoute := &SendRoute{
+		sendUC:        mockSendUC,
+		idempotencyUC: mockIdempotencyUC,
+		wp:            mockWorkerPool,
+		log:           log,
+	}
+
+	sendRoute.Send(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.JSONEq(t, `"Coins sent successfully"`, w.Body.String())
+
+	mockSendUC.AssertNotCalled(t, "SendCoin", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockIdempotencyUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestSendRoute_Send_IdempotencyKeyReused(t *testing.T) {
+	mockSendUC := new(sendmocks.Send)
+	mockIdempotencyUC := new(idempotencymocks.Idempotency)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockIdempotencyUC.On("Do", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, false, e.ErrIdempotencyKeyReused)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"toUser": "receiverUser", "amount": 200}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/sendCoin", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("Idempotency-Key", "key-1")
+	c.Set("username", "senderUser")
+
+	sendRoute := &SendRoute{
+		sendUC:        mockSendUC,
+		idempotencyUC: mockIdempotencyUC,
+		wp:            mockWorkerPool,
+		log:           log,
+	}
+
+	sendRoute.Send(c)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"error":"Idempotency key reused with different request"}`, w.Body.String())
+
+	mockIdempotencyUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestSendRoute_Send_WithMessage(t *testing.T) {
+	mockSendUC := new(sendmocks.Send)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockSendUC.On("SendCoin", mock.Anything, "senderUser", "receiverUser", 100, "thanks for the review").Return(nil, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"toUser": "receiverUser", "amount": 100, "message": "thanks for the review"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/sendCoin", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "senderUser")
+
+	sendRoute := &SendRoute{
+		sendUC: mockSendUC,
+		wp:     mockWorkerPool,
+		log:    log,
+	}
+
+	sendRoute.Send(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockSendUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestSendRoute_Send_MessageTooLong(t *testing.T) {
+	mockSendUC := new(sendmocks.Send)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"toUser": "receiverUser", "amount": 100, "message": "` + strings.Repeat("a", 201) + `"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/sendCoin", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "senderUser")
+
+	sendRoute := &SendRoute{
+		sendUC: mockSendUC,
+		wp:     mockWorkerPool,
+		log:    log,
+	}
+
+	sendRoute.Send(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"Invalid request"}`, w.Body.String())
+}
+
+func TestSendRoute_SendBatch_Success(t *testing.T) {
+	mockSendUC := new(sendmocks.Send)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockSendUC.On("SendCoinBatch", mock.Anything, "senderUser", []entity.Transfer{
+		{ToUser: "user1", Amount: 10},
+		{ToUser: "user2", Amount: 20},
+	}).Return(nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"transfers": [{"toUser": "user1", "amount": 10}, {"toUser": "user2", "amount": 20}]}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/sendCoin/batch", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "senderUser")
+
+	sendRoute := &SendRoute{
+		sendUC: mockSendUC,
+		wp:     mockWorkerPool,
+		log:    log,
+	}
+
+	sendRoute.SendBatch(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `"Coins sent successfully"`, w.Body.String())
+
+	mockSendUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestSendRoute_SendBatch_InvalidTransfer(t *testing.T) {
+	mockSendUC := new(sendmocks.Send)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"transfers": [{"toUser": "user1", "amount": 10}, {"toUser": "", "amount": 0}]}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/sendCoin/batch", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "senderUser")
+
+	sendRoute := &SendRoute{
+		sendUC: mockSendUC,
+		wp:     mockWorkerPool,
+		log:    log,
+	}
+
+	sendRoute.SendBatch(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"Invalid request"}`, w.Body.String())
+}
+
+func TestSendRoute_Send_PendingApproval(t *testing.T) {
+	mockSendUC := new(sendmocks.Send)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockSendUC.On("SendCoin", mock.Anything, "senderUser", "receiverUser", 5000, "").Return(&entity.PendingTransfer{
+		ID:        3,
+		FromUser:  "senderUser",
+		ToUser:    "receiverUser",
+		Amount:    5000,
+		Status:    entity.PendingTransferPending,
+		CreatedAt: time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC),
+	}, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"toUser": "receiverUser", "amount": 5000}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/sendCoin", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "senderUser")
+
+	sendRoute := &SendRoute{
+		sendUC: mockSendUC,
+		wp:     mockWorkerPool,
+		log:    log,
+	}
+
+	sendRoute.Send(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{
+		"id": 3,
+		"fromUser": "senderUser",
+		"toUser": "receiverUser",
+		"amount": 5000,
+		"status": "pending",
+		"createdAt": "2030-01-01T12:00:00Z"
+	}`, w.Body.String())
+
+	mockSendUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
+
+func TestSendRoute_Send_LimitExceeded(t *testing.T) {
+	mockSendUC := new(sendmocks.Send)
+	mockWorkerPool := new(workermocks.PoolI)
+	log := slog.Default()
+
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	mockSendUC.On("SendCoin", mock.Anything, "senderUser", "receiverUser", 100, "").Return(nil, e.ErrLimitExceeded)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"toUser": "receiverUser", "amount": 100}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/sendCoin", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("username", "senderUser")
+
+	sendRoute := &SendRoute{
+		sendUC: mockSendUC,
+		wp:     mockWorkerPool,
+		log:    log,
+	}
+
+	sendRoute.Send(c)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.JSONEq(t, `{"error": "Send limit exceeded"}`, w.Body.String())
+
+	mockSendUC.AssertExpectations(t)
+	mockWorkerPool.AssertExpectations(t)
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	"avito-shop/internal/entity"
+	"avito-shop/internal/usecase/statement"
+	e "avito-shop/pkg/errors"
+	mw "avito-shop/pkg/jwt"
+)
+
+const (
+	// statementFlushEvery is how many lines are buffered before they are sent.
+	statementFlushEvery = 100
+	// statementWriteTimeout replaces the server write timeout for an export:
+	// it is renewed before every chunk, so a long statement is not cut off as
+	// long as each chunk makes it to the client in time.
+	statementWriteTimeout = 30 * time.Second
+)
+
+var statementHeader = []string{"entryId", "createdAt", "kind", "counterparty", "reference", "amount", "balance"}
+
+type StatementRoute struct {
+	statementUC statement.Statement
+	log         *slog.Logger
+	wp          worker.PoolI
+}
+
+func NewStatementRoute(handler *gin.RouterGroup, statementUC statement.Statement, wp worker.PoolI, log *slog.Logger) {
+	r := &StatementRoute{statementUC, log, wp}
+	handler.GET("/statement", mw.AuthMW(), r.Export)
+}
+
+type StatementQuery struct {
+	From   *time.Time `form:"from"   time_format:"2006-01-02T15:04:05Z07:00"`
+	To     *time.Time `form:"to"     time_format:"2006-01-02T15:04:05Z07:00"`
+	Format string     `form:"format" binding:"omitempty,oneof=csv jsonl"`
+}
+
+// Export streams the statement line by line. Headers are sent with the first
+// line, so errors before it still get a JSON response; later errors can only
+// cut the stream short.
+func (r *StatementRoute) Export(c *gin.Context) {
+	username, exists := c.Get("username")
+	if !exists {
+		requestLog(c, r.log).Error("Username not found in context")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+
+		return
+	}
+
+	var query StatementQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	if query.Format == "" {
+		query.Format = entity.StatementFormatCSV
+	}
+
+	r.extendWriteDeadline(c)
+
+	filter := entity.StatementFilter{
+		Username: username.(string),
+		From:     query.From,
+		To:       query.To,
+	}
+
+	var (
+		enc   statementEncoder
+		lines int
+	)
+
+	// The task writes the response itself, so unlike submitAndWait this waits
+	// for it even after the client is gone; the cancelled ctx stops the export.
+	ctx := c.Request.Context()
+	done := make(chan error, 1)
+
+	err := r.wp.Submit(ctx, func(ctx context.Context) {
+		done <- worker.Catch(func() error {
+			return r.statementUC.Export(ctx, filter, func(line entity.StatementLine) error {
+				if enc == nil {
+					enc = beginStatement(c, query.Format)
+				}
+
+				if err := enc.Encode(line); err != nil {
+					return err
+				}
+
+				lines++
+				if lines%statementFlushEvery == 0 {
+					r.extendWriteDeadline(c)
+
+					return enc.Flush()
+				}
+
+				return nil
+			})
+		})
+	})
+	if err == nil {
+		err = <-done
+	}
+
+	if err != nil {
+		requestLog(c, r.log).Error("Failed to export statement", slog.String("error", err.Error()))
+
+		if enc != nil {
+			c.Abort()
+
+			return
+		}
+
+		if abortUnavailable(c, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, e.ErrInvalidRequest):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export statement"})
+		}
+
+		return
+	}
+
+	if enc == nil {
+		enc = beginStatement(c, query.Format)
+	}
+
+	r.extendWriteDeadline(c)
+
+	if err = enc.Flush(); err != nil {
+		requestLog(c, r.log).Error("Failed to write statement", slog.String("error", err.Error()))
+	}
+}
+
+// extendWriteDeadline gives the next chunk statementWriteTimeout to be written.
+// Writers without deadlines, such as test recorders, are left as they are.
+func (r *StatementRoute) extendWriteDeadline(c *gin.Context) {
+	err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(statementWriteTimeout))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		requestLog(c, r.log).Warn("Failed to extend write deadline", slog.String("error", err.Error()))
+	}
+}
+
+type statementEncoder interface {
+	Encode(line entity.StatementLine) error
+	Flush() error
+}
+
+// beginStatement sends the response headers and returns an encoder writing
+// to the response body.
+func beginStatement(c *gin.Context, format string) statementEncoder {
+	buf := bufio.NewWriter(c.Writer)
+
+	c.Header("Content-Disposition", "attachment; filename=statement."+format)
+
+	if format == entity.StatementFormatJSONL {
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+
+		return &jsonlEncoder{c.Writer, buf, json.NewEncoder(buf)}
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	enc := &csvEncoder{c.Writer, buf, csv.NewWriter(buf)}
+	_ = enc.w.Write(statementHeader)
+
+	return enc
+}
+
+type csvEncoder struct {
+	rw  gin.ResponseWriter
+	buf *bufio.Writer
+	w   *csv.Writer
+}
+
+func (enc *csvEncoder) Encode(line entity.StatementLine) error {
+	return enc.w.Write([]string{
+		strconv.Itoa(line.EntryID),
+		line.CreatedAt.UTC().Format(time.RFC3339),
+		line.Kind,
+		line.Counterparty,
+		line.Reference,
+		strconv.Itoa(line.Amount),
+		strconv.Itoa(line.Balance),
+	})
+}
+
+func (enc *csvEncoder) Flush() error {
+	enc.w.Flush()
+	if err := enc.w.Error(); err != nil {
+		return err
+	}
+
+	if err := enc.buf.Flush(); err != nil {
+		return err
+	}
+
+	enc.rw.Flush()
+
+	return nil
+}
+
+type jsonlEncoder struct {
+	rw  gin.ResponseWriter
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func (enc *jsonlEncoder) Encode(line entity.StatementLine) error {
+	return enc.enc.Encode(line)
+}
+
+func (enc *jsonlEncoder) Flush() error {
+	if err := enc.buf.Flush(); err != nil {
+		return err
+	}
+
+	enc.rw.Flush()
+
+	return nil
+}
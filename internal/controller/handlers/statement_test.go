@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	workermocks "avito-shop/internal/controller/worker/mocks"
+	"avito-shop/internal/entity"
+	statementmocks "avito-shop/internal/usecase/statement/mocks"
+)
+
+var statementLines = []entity.StatementLine{
+	{
+		EntryID:      3,
+		CreatedAt:    time.Date(2030, time.January, 1, 9, 0, 0, 0, time.UTC),
+		Kind:         entity.LedgerKindGrant,
+		Counterparty: entity.MintAccount,
+		Amount:       1000,
+		Balance:      1000,
+	},
+	{
+		EntryID:      7,
+		CreatedAt:    time.Date(2030, time.January, 2, 9, 0, 0, 0, time.UTC),
+		Kind:         entity.LedgerKindPurchase,
+		Counterparty: entity.ShopAccount,
+		Reference:    "t-shirt",
+		Amount:       -80,
+		Balance:      920,
+	},
+}
+
+func newStatementRoute(uc *statementmocks.Statement) *StatementRoute {
+	mockWorkerPool := new(workermocks.PoolI)
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	return &StatementRoute{
+		statementUC: uc,
+		wp:          mockWorkerPool,
+		log:         slog.Default(),
+	}
+}
+
+func streamLines(lines []entity.StatementLine) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		fn := args.Get(2).(func(entity.StatementLine) error)
+		for _, line := range lines {
+			_ = fn(line)
+		}
+	}
+}
+
+func TestStatementRoute_Export_CSV(t *testing.T) {
+	mockStatementUC := new(statementmocks.Statement)
+	mockStatementUC.On("Export", mock.Anything, mock.MatchedBy(func(f entity.StatementFilter) bool {
+		return f.Username == "alice" && f.From != nil && f.To == nil
+	}), mock.Anything).Run(streamLines(statementLines)).Return(nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/statement?from=2030-01-01T00:00:00Z", http.NoBody)
+	c.Set("username", "alice")
+
+	newStatementRoute(mockStatementUC).Export(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "entryId,createdAt,kind,counterparty,reference,amount,balance\n"+
+		"3,2030-01-01T09:00:00Z,grant,system:mint,,1000,1000\n"+
+		"7,2030-01-02T09:00:00Z,purchase,system:shop,t-shirt,-80,920\n", w.Body.String())
+}
+
+func TestStatementRoute_Export_JSONL(t *testing.T) {
+	mockStatementUC := new(statementmocks.Statement)
+	mockStatementUC.On("Export", mock.Anything, mock.Anything, mock.Anything).
+		Run(streamLines(statementLines)).Return(nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/statement?format=jsonl", http.NoBody)
+	c.Set("username", "alice")
+
+	newStatementRoute(mockStatementUC).Export(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Equal(t,
+		`{"entryId":3,"createdAt":"2030-01-01T09:00:00Z","kind":"grant","counterparty":"system:mint","amount":1000,"balance":1000}`+"\n"+
+			`{"entryId":7,"createdAt":"2030-01-02T09:00:00Z","kind":"purchase","counterparty":"system:shop","reference":"t-shirt","amount":-80,"balance":920}`+"\n",
+		w.Body.String())
+}
+
+func TestStatementRoute_Export_Empty(t *testing.T) {
+	mockStatementUC := new(statementmocks.Statement)
+	mockStatementUC.On("Export", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/statement", http.NoBody)
+	c.Set("username", "alice")
+
+	newStatementRoute(mockStatementUC).Export(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "entryId,createdAt,kind,counterparty,reference,amount,balance\n", w.Body.String())
+}
+
+func TestStatementRoute_Export_InvalidFormat(t *testing.T) {
+	mockStatementUC := new(statementmocks.Statement)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/statement?format=xlsx", http.NoBody)
+	c.Set("username", "alice")
+
+	newStatementRoute(mockStatementUC).Export(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "Invalid request"}`, w.Body.String())
+	mockStatementUC.AssertNotCalled(t, "Export", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStatementRoute_Export_ErrorBeforeFirstLine(t *testing.T) {
+	mockStatementUC := new(statementmocks.Statement)
+	mockStatementUC.On("Export", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/statement", http.NoBody)
+	c.Set("username", "alice")
+
+	newStatementRoute(mockStatementUC).Export(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error": "Failed to export statement"}`, w.Body.String())
+}
+
+func TestStatementRoute_Export_OutlivesWriteTimeout(t *testing.T) {
+	lines := make([]entity.StatementLine, 3*statementFlushEvery)
+	for i := range lines {
+		lines[i] = statementLines[i%len(statementLines)]
+	}
+
+	// Every chunk is slow, so together they take longer than the server
+	// write timeout allows for a whole response.
+	mockStatementUC := new(statementmocks.Statement)
+	mockStatementUC.On("Export", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(entity.StatementLine) error)
+		for i, line := range lines {
+			if i%statementFlushEvery == 0 {
+				time.Sleep(100 * time.Millisecond)
+			}
+
+			_ = fn(line)
+		}
+	}).Return(nil)
+
+	gin.SetMode(gin.TestMode)
+
+	route := newStatementRoute(mockStatementUC)
+	engine := gin.New()
+	engine.GET("/statement", func(c *gin.Context) { c.Set("username", "alice") }, route.Export)
+
+	srv := httptest.NewUnstartedServer(engine)
+	srv.Config.WriteTimeout = 150 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/statement")
+	assert.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, len(lines)+1, strings.Count(string(body), "\n"))
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	"avito-shop/internal/entity"
+	"avito-shop/internal/usecase/stats"
+	e "avito-shop/pkg/errors"
+	mw "avito-shop/pkg/jwt"
+)
+
+type StatsRoute struct {
+	statsUC stats.Stats
+	log     *slog.Logger
+	wp      worker.PoolI
+}
+
+func NewStatsRoute(handler *gin.RouterGroup, statsUC stats.Stats, wp worker.PoolI, log *slog.Logger) {
+	r := &StatsRoute{statsUC, log, wp}
+
+	g := handler.Group("/admin/stats", mw.AuthMW(), mw.AdminMW())
+	{
+		g.GET("", r.Economy)
+		g.GET("/balances", r.Balances)
+		g.GET("/daily", r.Daily)
+		g.GET("/items", r.Items)
+	}
+}
+
+// StatsRangeQuery selects rollup days; both bounds are inclusive UTC dates.
+type StatsRangeQuery struct {
+	From *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To   *time.Time `form:"to"   time_format:"2006-01-02" time_utc:"1"`
+}
+
+// Economy returns live totals of the coin economy.
+func (r *StatsRoute) Economy(c *gin.Context) {
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (*entity.EconomyStats, error) {
+		return r.statsUC.Economy(ctx)
+	})
+	if err != nil {
+		r.fail(c, err, "Failed to get economy stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Balances returns the live distribution of user balances.
+func (r *StatsRoute) Balances(c *gin.Context) {
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) (*entity.BalanceDistribution, error) {
+		return r.statsUC.BalanceDistribution(ctx)
+	})
+	if err != nil {
+		r.fail(c, err, "Failed to get balance distribution")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Daily returns the materialized daily rollups; the current day is not included.
+func (r *StatsRoute) Daily(c *gin.Context) {
+	var query StatsRangeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) ([]entity.DailyStats, error) {
+		return r.statsUC.Daily(ctx, entity.StatsRange{From: query.From, To: query.To})
+	})
+	if err != nil {
+		r.fail(c, err, "Failed to get daily stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Items returns item sales per day from the materialized rollups.
+func (r *StatsRoute) Items(c *gin.Context) {
+	var query StatsRangeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		requestLog(c, r.log).Error("Failed to parse request", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+
+		return
+	}
+
+	result, err := submitAndWait(c.Request.Context(), r.wp, func(ctx context.Context) ([]entity.ItemSales, error) {
+		return r.statsUC.ItemSales(ctx, entity.StatsRange{From: query.From, To: query.To})
+	})
+	if err != nil {
+		r.fail(c, err, "Failed to get item sales")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (r *StatsRoute) fail(c *gin.Context, err error, failMsg string) {
+	requestLog(c, r.log).Error(failMsg, slog.String("error", err.Error()))
+
+	if abortUnavailable(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, e.ErrInvalidRequest):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failMsg})
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/controller/worker"
+	workermocks "avito-shop/internal/controller/worker/mocks"
+	"avito-shop/internal/entity"
+	statsmocks "avito-shop/internal/usecase/stats/mocks"
+	e "avito-shop/pkg/errors"
+)
+
+func newStatsRoute(uc *statsmocks.Stats) *StatsRoute {
+	mockWorkerPool := new(workermocks.PoolI)
+	mockWorkerPool.On("Submit", mock.Anything, mock.AnythingOfType("worker.Task")).Run(func(args mock.Arguments) {
+		task := args.Get(1).(worker.Task)
+		task(args.Get(0).(context.Context))
+	}).Return(nil)
+
+	return &StatsRoute{
+		statsUC: uc,
+		wp:      mockWorkerPool,
+		log:     slog.Default(),
+	}
+}
+
+func TestStatsRoute_Economy_Success(t *testing.T) {
+	mockStatsUC := new(statsmocks.Stats)
+	mockStatsUC.On("Economy", mock.Anything).Return(&entity.EconomyStats{
+		Users:          3,
+		Circulation:    2500,
+		Minted:         3000,
+		ClawedBack:     100,
+		Expired:        0,
+		SpentInShop:    400,
+		VelocityWindow: "720h0m0s",
+		Transferred:    500,
+		Transfers:      7,
+		Velocity:       0.2,
+	}, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/stats", http.NoBody)
+
+	newStatsRoute(mockStatsUC).Economy(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"users": 3,
+		"circulation": 2500,
+		"minted": 3000,
+		"clawedBack": 100,
+		"expired": 0,
+		"spentInShop": 400,
+		"velocityWindow": "720h0m0s",
+		"transferred": 500,
+		"transfers": 7,
+		"velocity": 0.2
+	}`, w.Body.String())
+}
+
+func TestStatsRoute_Daily_Range(t *testing.T) {
+	mockStatsUC := new(statsmocks.Stats)
+
+	from := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2030, time.January, 2, 0, 0, 0, 0, time.UTC)
+
+	mockStatsUC.On("Daily", mock.Anything, mock.MatchedBy(func(r entity.StatsRange) bool {
+		return r.From != nil && r.From.Equal(from) && r.To != nil && r.To.Equal(to)
+	})).Return([]entity.DailyStats{
+		{Day: from, Minted: 1000, Transferred: 50, Transfers: 2, Circulation: 1000},
+		{Day: to, SpentInShop: 80, Circulation: 920},
+	}, nil)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/stats/daily?from=2030-01-01&to=2030-01-02", http.NoBody)
+
+	newStatsRoute(mockStatsUC).Daily(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[
+		{"day": "2030-01-01T00:00:00Z", "minted": 1000, "clawedBack": 0, "expired": 0, "spentInShop": 0,
+			"transferred": 50, "transfers": 2, "circulation": 1000},
+		{"day": "2030-01-02T00:00:00Z", "minted": 0, "clawedBack": 0, "expired": 0, "spentInShop": 80,
+			"transferred": 0, "transfers": 0, "circulation": 920}
+	]`, w.Body.String())
+}
+
+func TestStatsRoute_Items_InvalidRange(t *testing.T) {
+	mockStatsUC := new(statsmocks.Stats)
+	mockStatsUC.On("ItemSales", mock.Anything, mock.Anything).Return(nil, e.ErrInvalidRequest)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/stats/items?from=2030-02-01&to=2030-01-01", http.NoBody)
+
+	newStatsRoute(mockStatsUC).Items(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "Invalid request"}`, w.Body.String())
+}
+
+func TestStatsRoute_Items_BadDate(t *testing.T) {
+	mockStatsUC := new(statsmocks.Stats)
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/admin/stats/items?from=yesterday", http.NoBody)
+
+	newStatsRoute(mockStatsUC).Items(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockStatsUC.AssertNotCalled(t, "ItemSales", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,64 @@
+package reconciler
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/usecase/reconcile"
+	"avito-shop/pkg/logger/sl"
+	"avito-shop/pkg/metrics"
+	"avito-shop/pkg/periodic"
+)
+
+// Reconciler periodically checks balances against the operation history.
+type Reconciler struct {
+	*periodic.Runner
+	reconcileUC reconcile.Reconcile
+	log         *slog.Logger
+}
+
+func New(reconcileUC reconcile.Reconcile, log *slog.Logger, interval time.Duration) *Reconciler {
+	r := &Reconciler{
+		reconcileUC: reconcileUC,
+		log:         log,
+	}
+	r.Runner = periodic.New(interval, func(ctx context.Context) { _ = r.RunOnce(ctx) })
+
+	return r
+}
+
+// RunOnce performs a single reconciliation, logs drifted users and updates
+// the metrics.
+func (r *Reconciler) RunOnce(ctx context.Context) error {
+	const op = "reconciler.RunOnce"
+
+	report, err := r.reconcileUC.Run(ctx, time.Now())
+	if err != nil {
+		r.log.Error("failed to reconcile balances", slog.String("op", op), sl.Err(err))
+		return err
+	}
+
+	metrics.Reconciled(len(report.Drifts), report.TotalDrift, report.CheckedAt)
+
+	for _, d := range report.Drifts {
+		r.log.Warn("balance drift",
+			slog.String("op", op),
+			slog.Int("report", report.ID),
+			slog.String("username", d.Username),
+			slog.Int("expected", d.Expected),
+			slog.Int("actual", d.Actual),
+		)
+	}
+
+	r.log.Info("balances reconciled",
+		slog.String("op", op),
+		slog.Int("report", report.ID),
+		slog.Int("users", report.Users),
+		slog.Int("drifted", len(report.Drifts)),
+		slog.Int("totalDrift", report.TotalDrift),
+	)
+
+	return nil
+}
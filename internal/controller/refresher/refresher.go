@@ -0,0 +1,38 @@
+package refresher
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/usecase/analytics"
+	"avito-shop/pkg/logger/sl"
+	"avito-shop/pkg/periodic"
+)
+
+// Refresher periodically rebuilds the cached leaderboards. Start warms the
+// cache right away and then refreshes it on every tick.
+type Refresher struct {
+	*periodic.Runner
+	analyticsUC analytics.Analytics
+	log         *slog.Logger
+}
+
+func New(analyticsUC analytics.Analytics, log *slog.Logger, interval time.Duration) *Refresher {
+	r := &Refresher{
+		analyticsUC: analyticsUC,
+		log:         log,
+	}
+	r.Runner = periodic.New(interval, r.tick, periodic.Immediately())
+
+	return r
+}
+
+func (r *Refresher) tick(ctx context.Context) {
+	const op = "refresher.tick"
+
+	if err := r.analyticsUC.Refresh(ctx); err != nil {
+		r.log.Error("failed to refresh leaderboards", slog.String("op", op), sl.Err(err))
+	}
+}
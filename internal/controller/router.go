@@ -2,64 +2,94 @@
 package controller
 
 import (
-	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
-	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"net/http"
+	"sync/atomic"
+
 	// Swagger docs.
 	_ "github.com/evrone/go-clean-template/docs"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"golang.org/x/exp/slog"
 
 	h "avito-shop/internal/controller/handlers"
 	"avito-shop/internal/controller/worker"
-	repo "avito-shop/internal/repository"
+	"avito-shop/internal/usecase/analytics"
 	"avito-shop/internal/usecase/auth"
 	"avito-shop/internal/usecase/buy"
+	"avito-shop/internal/usecase/coinrequest"
+	"avito-shop/internal/usecase/grant"
+	"avito-shop/internal/usecase/health"
+	"avito-shop/internal/usecase/history"
+	"avito-shop/internal/usecase/idempotency"
 	"avito-shop/internal/usecase/info"
+	"avito-shop/internal/usecase/reversal"
+	"avito-shop/internal/usecase/schedule"
 	"avito-shop/internal/usecase/send"
-	"avito-shop/pkg/postgres"
+	"avito-shop/internal/usecase/statement"
+	"avito-shop/internal/usecase/stats"
+	"avito-shop/pkg/logger"
+	"avito-shop/pkg/metrics"
+	"avito-shop/pkg/tracing"
 )
 
+// UseCases are the use cases served over HTTP. They are built once in app and
+// shared with the background jobs, so both go through the same transaction
+// manager and policies.
+type UseCases struct {
+	Analytics   analytics.Analytics
+	Approval    send.Approval
+	Auth        auth.Auth
+	Buy         buy.Buy
+	CoinRequest coinrequest.CoinRequest
+	Grant       grant.Grant
+	Health      health.Health
+	History     history.History
+	Idempotency idempotency.Idempotency
+	Info        info.Info
+	Reversal    reversal.Reversal
+	Schedule    schedule.Schedule
+	Send        send.Send
+	Statement   statement.Statement
+	Stats       stats.Stats
+}
+
 func NewRouter(handler *gin.Engine,
 	log *slog.Logger,
-	pg *postgres.Postgres,
-	wp *worker.Pool,
+	pools *worker.Pools,
+	uc UseCases,
+	ready *atomic.Bool,
 ) {
 	// options
-	handler.Use(gin.Logger())
+	handler.Use(logger.RequestID(log))
+	handler.Use(logger.AccessLog(log))
 	handler.Use(gin.Recovery())
+	handler.Use(metrics.HTTP())
+	handler.Use(tracing.HTTP())
 
-	// use cases
-	authUseCase := auth.New(
-		repo.NewUserRepo(pg),
-		repo.NewBalanceRepo(pg),
-		manager.Must(trmsqlx.NewDefaultFactory(pg.Pool)),
-	)
-
-	buyUseCase := buy.New(
-		repo.NewBalanceRepo(pg),
-		repo.NewInventoryRepo(pg),
-		manager.Must(trmsqlx.NewDefaultFactory(pg.Pool)),
-	)
-
-	infoUseCase := info.New(
-		repo.NewBalanceRepo(pg),
-		repo.NewInventoryRepo(pg),
-		repo.NewTransactionRepo(pg),
-		manager.Must(trmsqlx.NewDefaultFactory(pg.Pool)),
-	)
-
-	sendUseCase := send.New(
-		repo.NewBalanceRepo(pg),
-		repo.NewTransactionRepo(pg),
-		manager.Must(trmsqlx.NewDefaultFactory(pg.Pool)),
-	)
+	// probes
+	h.NewHealthRoute(&handler.RouterGroup, uc.Health, pools, ready, log)
 
 	// router
 	v1 := handler.Group("/api")
 	{
-		h.NewAuthRoute(v1, authUseCase, wp, log)
-		h.NewBuyRoute(v1, buyUseCase, wp, log)
-		h.NewInfoRoute(v1, infoUseCase, wp, log)
-		h.NewSendRoute(v1, sendUseCase, wp, log)
+		h.NewAuthRoute(v1, uc.Auth, pools.Auth, log)
+		h.NewBuyRoute(v1, uc.Buy, uc.Idempotency, pools.Writes, log)
+		h.NewInfoRoute(v1, uc.Info, pools.Reads, log)
+		h.NewHistoryRoute(v1, uc.History, pools.Reads, log)
+		h.NewStatementRoute(v1, uc.Statement, pools.Exports, log)
+		h.NewSendRoute(v1, uc.Send, uc.Idempotency, pools.Writes, log)
+		h.NewScheduleRoute(v1, uc.Schedule, pools.Writes, log)
+		h.NewCoinRequestRoute(v1, uc.CoinRequest, pools.Writes, log)
+		h.NewGrantRoute(v1, uc.Grant, pools.Writes, log)
+		h.NewApprovalRoute(v1, uc.Approval, pools.Writes, log)
+		h.NewReversalRoute(v1, uc.Reversal, pools.Writes, log)
+		h.NewLeaderboardRoute(v1, uc.Analytics, pools.Reads, log)
+		h.NewStatsRoute(v1, uc.Stats, pools.Reads, log)
 	}
 }
+
+// NewInternalRouter serves the endpoints meant for the cluster only, on the
+// internal port.
+func NewInternalRouter(handler *http.ServeMux) {
+	handler.Handle("/metrics", promhttp.Handler())
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"avito-shop/internal/usecase/schedule"
+	"avito-shop/pkg/logger/sl"
+	"avito-shop/pkg/periodic"
+)
+
+// Scheduler periodically executes due scheduled transfers. Several app
+// instances may run it at once: due rows are claimed with row locks.
+type Scheduler struct {
+	*periodic.Runner
+	scheduleUC schedule.Schedule
+	log        *slog.Logger
+}
+
+func New(scheduleUC schedule.Schedule, log *slog.Logger, interval time.Duration) *Scheduler {
+	s := &Scheduler{
+		scheduleUC: scheduleUC,
+		log:        log,
+	}
+	s.Runner = periodic.New(interval, s.tick)
+
+	return s
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	const op = "scheduler.tick"
+
+	n, err := s.scheduleUC.RunDue(ctx, time.Now())
+	if err != nil {
+		s.log.Error("failed to run scheduled transfers", slog.String("op", op), sl.Err(err))
+	}
+
+	if n > 0 {
+		s.log.Info("scheduled transfers processed", slog.String("op", op), slog.Int("count", n))
+	}
+}
@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// collector exports the Stats of every pool labelled by its name.
+type collector struct {
+	pools map[string]*Pool
+
+	workers   *prometheus.Desc
+	capacity  *prometheus.Desc
+	queued    *prometheus.Desc
+	busy      *prometheus.Desc
+	rejected  *prometheus.Desc
+	saturated *prometheus.Desc
+}
+
+// NewCollector returns a Prometheus collector of the pools' stats.
+func NewCollector(pools *Pools) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("avito_shop", "worker_pool", name), help, []string{"pool"}, nil)
+	}
+
+	return &collector{
+		pools:     pools.byName(),
+		workers:   desc("workers", "Worker goroutines."),
+		capacity:  desc("queue_capacity", "Size of the task queue."),
+		queued:    desc("queue_depth", "Tasks waiting in the queue."),
+		busy:      desc("busy_workers", "Workers running a task."),
+		rejected:  desc("rejected_total", "Tasks refused because the pool was saturated or closed."),
+		saturated: desc("saturated", "1 when the queue is full and new tasks wait or are refused."),
+	}
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.workers
+	ch <- c.capacity
+	ch <- c.queued
+	ch <- c.busy
+	ch <- c.rejected
+	ch <- c.saturated
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	for name, pool := range c.pools {
+		s := pool.Stats()
+
+		ch <- prometheus.MustNewConstMetric(c.workers, prometheus.GaugeValue, float64(s.Workers), name)
+		ch <- prometheus.MustNewConstMetric(c.capacity, prometheus.GaugeValue, float64(s.Capacity), name)
+		ch <- prometheus.MustNewConstMetric(c.queued, prometheus.GaugeValue, float64(s.Queued), name)
+		ch <- prometheus.MustNewConstMetric(c.busy, prometheus.GaugeValue, float64(s.Busy), name)
+		ch <- prometheus.MustNewConstMetric(c.rejected, prometheus.CounterValue, float64(s.Rejected), name)
+
+		saturated := 0.0
+		if s.Queued >= s.Capacity {
+			saturated = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.saturated, prometheus.GaugeValue, saturated, name)
+	}
+}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	worker "avito-shop/internal/controller/worker"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -18,9 +19,22 @@ func (_m *PoolI) Shutdown() {
 	_m.Called()
 }
 
-// Submit provides a mock function with given fields: task
-func (_m *PoolI) Submit(task worker.Task) {
-	_m.Called(task)
+// Submit provides a mock function with given fields: ctx, task
+func (_m *PoolI) Submit(ctx context.Context, task worker.Task) error {
+	ret := _m.Called(ctx, task)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Submit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, worker.Task) error); ok {
+		r0 = rf(ctx, task)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // NewPoolI creates a new instance of PoolI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
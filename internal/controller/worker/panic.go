@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	e "avito-shop/pkg/errors"
+)
+
+// PanicError is a recovered panic. It matches errors.Is(err, ErrTaskPanicked).
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("%s: %v", e.ErrTaskPanicked, p.Value)
+}
+
+func (p *PanicError) Unwrap() error {
+	return e.ErrTaskPanicked
+}
+
+// Catch runs fn and returns its error, or a *PanicError if fn panics.
+func Catch(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return fn()
+}
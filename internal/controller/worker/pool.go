@@ -1,25 +1,87 @@
 package worker
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
 )
 
-type Task func()
+const _defaultEnqueueTimeout = 50 * time.Millisecond
+
+// Task runs with the ctx it was submitted with, carrying the span of the
+// worker running it.
+type Task func(ctx context.Context)
+
+// job is a queued task along with what Submit knew about it.
+type job struct {
+	ctx      context.Context
+	task     Task
+	queuedAt time.Time
+}
 
 //go:generate mockery --name=PoolI
 type PoolI interface {
-	Submit(task Task)
+	Submit(ctx context.Context, task Task) error
 	Shutdown()
 }
 
 type Pool struct {
-	taskQueue chan Task
-	wg        sync.WaitGroup
+	name           string
+	taskQueue      chan job
+	numWorkers     int
+	enqueueTimeout time.Duration
+	onPanic        func(err error)
+	wg             sync.WaitGroup
+
+	busy     atomic.Int64
+	rejected atomic.Uint64
+
+	// mu keeps Shutdown from closing the queue under a pending Submit.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// Option -.
+type Option func(*Pool)
+
+// EnqueueTimeout sets how long Submit waits for room in a full queue; zero
+// makes it fail at once.
+func EnqueueTimeout(timeout time.Duration) Option {
+	return func(p *Pool) {
+		p.enqueueTimeout = timeout
+	}
+}
+
+// Name labels the spans of the pool's tasks.
+func Name(name string) Option {
+	return func(p *Pool) {
+		p.name = name
+	}
+}
+
+// PanicHandler receives the panics recovered from tasks as *PanicError.
+func PanicHandler(handler func(err error)) Option {
+	return func(p *Pool) {
+		p.onPanic = handler
+	}
 }
 
-func NewWorkerPool(numWorkers, numTask int) *Pool {
+func NewWorkerPool(numWorkers, numTask int, opts ...Option) *Pool {
 	pool := &Pool{
-		taskQueue: make(chan Task, numTask),
+		taskQueue:      make(chan job, numTask),
+		numWorkers:     numWorkers,
+		enqueueTimeout: _defaultEnqueueTimeout,
+		onPanic:        func(error) {},
+	}
+
+	for _, opt := range opts {
+		opt(pool)
 	}
 
 	pool.wg.Add(numWorkers)
@@ -34,16 +96,117 @@ func NewWorkerPool(numWorkers, numTask int) *Pool {
 func (p *Pool) worker() {
 	defer p.wg.Done()
 
-	for task := range p.taskQueue {
-		task()
+	for j := range p.taskQueue {
+		p.busy.Add(1)
+		p.run(j)
+		p.busy.Add(-1)
 	}
 }
 
-func (p *Pool) Submit(task Task) {
-	p.taskQueue <- task
+func (p *Pool) run(j job) {
+	ctx, span := tracing.Start(j.ctx, "worker.task")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("worker.pool", p.name),
+		attribute.Int64("worker.queue_wait_ms", time.Since(j.queuedAt).Milliseconds()),
+	)
+
+	err := Catch(func() error {
+		j.task(ctx)
+
+		return nil
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		p.onPanic(err)
+	}
 }
 
+// Submit queues the task. It never blocks longer than the enqueue timeout:
+// a queue that stays full yields ErrPoolSaturated, a cancelled ctx its error
+// and a pool that is shutting down ErrPoolClosed. A queued task always runs.
+func (p *Pool) Submit(ctx context.Context, task Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		p.rejected.Add(1)
+
+		return e.ErrPoolClosed
+	}
+
+	j := job{ctx: ctx, task: task, queuedAt: time.Now()}
+
+	select {
+	case p.taskQueue <- j:
+		return nil
+	default:
+	}
+
+	if p.enqueueTimeout <= 0 {
+		p.rejected.Add(1)
+
+		return e.ErrPoolSaturated
+	}
+
+	timer := time.NewTimer(p.enqueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case p.taskQueue <- j:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		p.rejected.Add(1)
+
+		return e.ErrPoolSaturated
+	}
+}
+
+// Stats is a point-in-time view of the pool.
+type Stats struct {
+	Workers  int
+	Capacity int
+	Queued   int
+	Busy     int
+	// Rejected counts Submit calls refused because the pool was saturated or closed.
+	Rejected uint64
+}
+
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Workers:  p.numWorkers,
+		Capacity: cap(p.taskQueue),
+		Queued:   len(p.taskQueue),
+		Busy:     int(p.busy.Load()),
+		Rejected: p.rejected.Load(),
+	}
+}
+
+// Closed reports whether the pool was shut down. A full queue is not closed:
+// saturation is exported as a metric and handled by Submit.
+func (p *Pool) Closed() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.closed
+}
+
+// Shutdown stops accepting tasks and waits for the queued ones to finish.
 func (p *Pool) Shutdown() {
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+
+		return
+	}
+
+	p.closed = true
 	close(p.taskQueue)
+	p.mu.Unlock()
+
 	p.wg.Wait()
 }
@@ -0,0 +1,50 @@
+package worker
+
+import "sort"
+
+// Pools are the bulkheads the routes are split across, so a flood of one
+// kind of request cannot starve the others.
+type Pools struct {
+	// Auth serves logins, which hash passwords.
+	Auth *Pool
+	// Writes serves requests that move coins.
+	Writes *Pool
+	// Reads serves everything else.
+	Reads *Pool
+	// Exports streams statements. A task lasts as long as the client takes
+	// to read the file, so slow clients only tie up this small pool.
+	Exports *Pool
+}
+
+// Unavailable returns the names of the pools that were shut down.
+func (p *Pools) Unavailable() []string {
+	var names []string
+
+	for name, pool := range p.byName() {
+		if pool.Closed() {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// byName keys the pools by the name used in metrics and probes.
+func (p *Pools) byName() map[string]*Pool {
+	return map[string]*Pool{
+		"auth":    p.Auth,
+		"writes":  p.Writes,
+		"reads":   p.Reads,
+		"exports": p.Exports,
+	}
+}
+
+// Shutdown stops every pool and waits for the queued tasks to finish.
+func (p *Pools) Shutdown() {
+	p.Auth.Shutdown()
+	p.Writes.Shutdown()
+	p.Reads.Shutdown()
+	p.Exports.Shutdown()
+}
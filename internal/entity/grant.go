@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+const (
+	GrantReasonOnboarding = "onboarding"
+	GrantAuthorSystem     = "system"
+)
+
+// CoinGrant mints coins to a user; a negative amount claws them back.
+type CoinGrant struct {
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`
+	Amount    int       `json:"amount"`
+	Reason    string    `json:"reason"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,9 @@
+package entity
+
+type IdempotencyKey struct {
+	Username    string `json:"username"`
+	Key         string `json:"key"`
+	RequestHash string `json:"requestHash"`
+	StatusCode  int    `json:"statusCode"`
+	Response    []byte `json:"response"`
+}
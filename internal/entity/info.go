@@ -5,3 +5,10 @@ type Info struct {
 	Inventory   []InventoryItem `json:"inventory"`
 	CoinHistory CoinHistory     `json:"coinHistory"`
 }
+
+// AggregatedInfo is Info with the coin history grouped by counterparty.
+type AggregatedInfo struct {
+	Coins       int                   `json:"coins"`
+	Inventory   []InventoryItem       `json:"inventory"`
+	CoinHistory AggregatedCoinHistory `json:"coinHistory"`
+}
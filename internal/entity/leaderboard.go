@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+const (
+	LeaderboardWindowWeek  = "week"
+	LeaderboardWindowMonth = "month"
+	LeaderboardWindowAll   = "all"
+)
+
+var LeaderboardWindows = []string{LeaderboardWindowWeek, LeaderboardWindowMonth, LeaderboardWindowAll}
+
+// LeaderboardSince returns the start of the window at now: the last seven
+// days, the current calendar month (UTC) or nil for the whole history.
+func LeaderboardSince(window string, now time.Time) (*time.Time, bool) {
+	switch window {
+	case LeaderboardWindowWeek:
+		since := now.AddDate(0, 0, -7)
+		return &since, true
+	case LeaderboardWindowMonth:
+		now = now.UTC()
+		since := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+		return &since, true
+	case LeaderboardWindowAll:
+		return nil, true
+	default:
+		return nil, false
+	}
+}
+
+// UserScore ranks a user by Coins. Count is the number of transfers, or the
+// number of distinct recipients on the most generous board.
+type UserScore struct {
+	Username string `json:"username"`
+	Coins    int    `json:"coins"`
+	Count    int    `json:"count"`
+}
+
+type ItemScore struct {
+	Item  string `json:"item"`
+	Count int    `json:"count"`
+}
+
+type Leaderboard struct {
+	Window       string      `json:"window"`
+	Since        *time.Time  `json:"since,omitempty"`
+	RefreshedAt  time.Time   `json:"refreshedAt"`
+	TopReceivers []UserScore `json:"topReceivers"`
+	TopSenders   []UserScore `json:"topSenders"`
+	MostGenerous []UserScore `json:"mostGenerous"`
+	MostBought   []ItemScore `json:"mostBought"`
+}
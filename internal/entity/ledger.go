@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	LedgerKindTransfer = "transfer"
+	LedgerKindPurchase = "purchase"
+	LedgerKindGrant    = "grant"
+	LedgerKindExpiry   = "expiry"
+	LedgerKindReversal = "reversal"
+
+	// ShopAccount collects coins spent on merch.
+	ShopAccount = "system:shop"
+	// MintAccount issues new coins; its balance is minus the coins in circulation.
+	MintAccount = "system:mint"
+
+	userAccountPrefix = "user:"
+)
+
+type LedgerEntry struct {
+	ID        int       `json:"id"`
+	Kind      string    `json:"kind"`
+	Reference string    `json:"reference,omitempty"`
+	Postings  []Posting `json:"postings"`
+}
+
+// Posting credits Amount to Account; a negative amount is a debit.
+type Posting struct {
+	Account string `json:"account"`
+	Amount  int    `json:"amount"`
+}
+
+func UserAccount(username string) string {
+	return userAccountPrefix + username
+}
+
+// AccountUser returns the username of a user account.
+func AccountUser(account string) (string, bool) {
+	return strings.CutPrefix(account, userAccountPrefix)
+}
+
+// Balanced reports whether the postings of the entry sum to zero.
+func (e LedgerEntry) Balanced() bool {
+	sum := 0
+	for _, p := range e.Postings {
+		sum += p.Amount
+	}
+
+	return len(e.Postings) > 0 && sum == 0
+}
+
+func TransferEntry(fromUser, toUser string, amount int) LedgerEntry {
+	return LedgerEntry{
+		Kind: LedgerKindTransfer,
+		Postings: []Posting{
+			{Account: UserAccount(fromUser), Amount: -amount},
+			{Account: UserAccount(toUser), Amount: amount},
+		},
+	}
+}
+
+func PurchaseEntry(username, item string, price int) LedgerEntry {
+	return LedgerEntry{
+		Kind:      LedgerKindPurchase,
+		Reference: item,
+		Postings: []Posting{
+			{Account: UserAccount(username), Amount: -price},
+			{Account: ShopAccount, Amount: price},
+		},
+	}
+}
+
+func GrantEntry(username string, amount int) LedgerEntry {
+	return LedgerEntry{
+		Kind: LedgerKindGrant,
+		Postings: []Posting{
+			{Account: MintAccount, Amount: -amount},
+			{Account: UserAccount(username), Amount: amount},
+		},
+	}
+}
+
+// ExpiryEntry burns expired coins back into the mint.
+func ExpiryEntry(username string, amount int) LedgerEntry {
+	return LedgerEntry{
+		Kind: LedgerKindExpiry,
+		Postings: []Posting{
+			{Account: UserAccount(username), Amount: -amount},
+			{Account: MintAccount, Amount: amount},
+		},
+	}
+}
+
+// ReversalEntry returns coins of a transfer from its recipient to its sender.
+func ReversalEntry(transactionID int, fromUser, toUser string, amount int) LedgerEntry {
+	return LedgerEntry{
+		Kind:      LedgerKindReversal,
+		Reference: strconv.Itoa(transactionID),
+		Postings: []Posting{
+			{Account: UserAccount(toUser), Amount: -amount},
+			{Account: UserAccount(fromUser), Amount: amount},
+		},
+	}
+}
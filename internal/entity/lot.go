@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+const (
+	LotSourceOpening  = "opening"
+	LotSourceGrant    = "grant"
+	LotSourceTransfer = "transfer"
+	LotSourceReversal = "reversal"
+)
+
+// CoinLot is a portion of a user's coins received at once. Spending consumes
+// the oldest lots first; whatever remains expires a fixed time after receipt.
+type CoinLot struct {
+	ID         int       `json:"id"`
+	Username   string    `json:"username"`
+	Source     string    `json:"source"`
+	Amount     int       `json:"amount"`
+	Remaining  int       `json:"remaining"`
+	ReceivedAt time.Time `json:"receivedAt"`
+}
+
+type ExpiredCoins struct {
+	Amount    int       `json:"amount"`
+	ExpiredAt time.Time `json:"expiredAt"`
+}
+
+// ExpirySummary totals all the coins of a user that expired.
+type ExpirySummary struct {
+	Amount int `json:"amount"`
+	Count  int `json:"count"`
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+const (
+	PendingTransferPending  = "pending"
+	PendingTransferApproved = "approved"
+	PendingTransferRejected = "rejected"
+)
+
+// PendingTransfer is a transfer above the approval threshold. No coins move
+// until an admin approves it.
+type PendingTransfer struct {
+	ID         int        `json:"id"`
+	FromUser   string     `json:"fromUser"`
+	ToUser     string     `json:"toUser"`
+	Amount     int        `json:"amount"`
+	Message    string     `json:"message,omitempty"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+	ResolvedBy string     `json:"resolvedBy,omitempty"`
+}
+
+// SendPolicy caps outgoing transfers. Zero disables a rule.
+type SendPolicy struct {
+	DailyLimit        int
+	MonthlyLimit      int
+	ApprovalThreshold int
+}
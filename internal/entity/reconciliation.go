@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// BalanceCheck compares the cached balance of a user with the balance derived
+// from grants, transfers and purchases.
+type BalanceCheck struct {
+	Username string `json:"username"`
+	Expected int    `json:"expected"`
+	Actual   int    `json:"actual"`
+}
+
+// Drift is positive when the user holds more coins than the history explains.
+func (c BalanceCheck) Drift() int {
+	return c.Actual - c.Expected
+}
+
+// DriftReport lists the users whose balance does not match their history.
+// TotalDrift is the sum of absolute drifts.
+type DriftReport struct {
+	ID         int            `json:"id"`
+	CheckedAt  time.Time      `json:"checkedAt"`
+	Users      int            `json:"users"`
+	TotalDrift int            `json:"totalDrift"`
+	Drifts     []BalanceCheck `json:"drifts"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+const (
+	RepeatNone    = ""
+	RepeatDaily   = "daily"
+	RepeatWeekly  = "weekly"
+	RepeatMonthly = "monthly"
+)
+
+type ScheduledTransfer struct {
+	ID        int       `json:"id"`
+	FromUser  string    `json:"fromUser"`
+	ToUser    string    `json:"toUser"`
+	Amount    int       `json:"amount"`
+	Message   string    `json:"message,omitempty"`
+	Repeat    string    `json:"repeat,omitempty"`
+	NextRunAt time.Time `json:"nextRunAt"`
+	// StartsAt is the first planned run. Monthly runs keep its day of month,
+	// falling back to the last day in shorter months.
+	StartsAt  time.Time  `json:"-"`
+	Active    bool       `json:"active"`
+	LastRunAt *time.Time `json:"lastRunAt,omitempty"`
+	LastError string     `json:"lastError,omitempty"`
+	Failures  int        `json:"failures"`
+}
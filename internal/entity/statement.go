@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+const (
+	StatementFormatCSV   = "csv"
+	StatementFormatJSONL = "jsonl"
+)
+
+// StatementLine is one coin movement of a user. Amount is negative for coins
+// leaving the account; Balance is the balance right after the movement.
+type StatementLine struct {
+	EntryID      int       `json:"entryId"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Kind         string    `json:"kind"`
+	Counterparty string    `json:"counterparty"`
+	Reference    string    `json:"reference,omitempty"`
+	Amount       int       `json:"amount"`
+	Balance      int       `json:"balance"`
+}
+
+// StatementFilter bounds a statement to [From, To); nil bounds are open.
+type StatementFilter struct {
+	Username string
+	From     *time.Time
+	To       *time.Time
+}
@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+// EconomyStats is a live snapshot of the coin economy. Transfer figures cover
+// the trailing VelocityWindow; Velocity is the volume moved in that window per
+// coin in circulation.
+type EconomyStats struct {
+	Users          int     `json:"users"`
+	Circulation    int     `json:"circulation"`
+	Minted         int     `json:"minted"`
+	ClawedBack     int     `json:"clawedBack"`
+	Expired        int     `json:"expired"`
+	SpentInShop    int     `json:"spentInShop"`
+	VelocityWindow string  `json:"velocityWindow"`
+	Transferred    int     `json:"transferred"`
+	Transfers      int     `json:"transfers"`
+	Velocity       float64 `json:"velocity"`
+}
+
+// DailyStats is the rollup of one UTC day. Circulation is the number of coins
+// held by users at the end of the day.
+type DailyStats struct {
+	Day         time.Time `json:"day"`
+	Minted      int       `json:"minted"`
+	ClawedBack  int       `json:"clawedBack"`
+	Expired     int       `json:"expired"`
+	SpentInShop int       `json:"spentInShop"`
+	Transferred int       `json:"transferred"`
+	Transfers   int       `json:"transfers"`
+	Circulation int       `json:"circulation"`
+}
+
+type ItemSales struct {
+	Day   time.Time `json:"day"`
+	Item  string    `json:"item"`
+	Sold  int       `json:"sold"`
+	Coins int       `json:"coins"`
+}
+
+// BalanceBucket counts users holding [From, To) coins; To is nil for the last bucket.
+type BalanceBucket struct {
+	From  int  `json:"from"`
+	To    *int `json:"to,omitempty"`
+	Users int  `json:"users"`
+}
+
+type BalanceDistribution struct {
+	Users   int             `json:"users"`
+	Min     int             `json:"min"`
+	Max     int             `json:"max"`
+	Mean    float64         `json:"mean"`
+	Median  float64         `json:"median"`
+	P90     float64         `json:"p90"`
+	P99     float64         `json:"p99"`
+	Buckets []BalanceBucket `json:"buckets"`
+}
+
+// StatsRange selects rollup days From..To inclusive; nil bounds are open.
+type StatsRange struct {
+	From *time.Time
+	To   *time.Time
+}
@@ -1,23 +1,98 @@
 package entity
 
+import "time"
+
 type CoinTransaction struct {
-	ID       int    `json:"id"`
-	FromUser string `json:"fromUser"`
-	ToUser   string `json:"toUser"`
-	Amount   int    `json:"amount"`
+	ID         int        `json:"id"`
+	FromUser   string     `json:"fromUser"`
+	ToUser     string     `json:"toUser"`
+	Amount     int        `json:"amount"`
+	Message    string     `json:"message,omitempty"`
+	ReversalOf *int       `json:"reversalOf,omitempty"`
+	ReversedAt *time.Time `json:"reversedAt,omitempty"`
+	// CreatedAt is unknown for transfers made before it was recorded.
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+type Transfer struct {
+	ToUser string `json:"toUser"`
+	Amount int    `json:"amount"`
 }
 
 type ReceivedTransaction struct {
+	ID        int        `json:"id"`
+	FromUser  string     `json:"fromUser"`
+	Amount    int        `json:"amount"`
+	Message   string     `json:"message,omitempty"`
+	Reversed  bool       `json:"reversed,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+type SentTransaction struct {
+	ID        int        `json:"id"`
+	ToUser    string     `json:"toUser"`
+	Amount    int        `json:"amount"`
+	Message   string     `json:"message,omitempty"`
+	Reversed  bool       `json:"reversed,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+// ReceivedSummary totals all transfers from one user.
+type ReceivedSummary struct {
 	FromUser string `json:"fromUser"`
 	Amount   int    `json:"amount"`
+	Count    int    `json:"count"`
 }
 
-type SentTransaction struct {
+// SentSummary totals all transfers to one user.
+type SentSummary struct {
 	ToUser string `json:"toUser"`
 	Amount int    `json:"amount"`
+	Count  int    `json:"count"`
+}
+
+type AggregatedCoinHistory struct {
+	Received []ReceivedSummary `json:"received"`
+	Sent     []SentSummary     `json:"sent"`
+	Expired  ExpirySummary     `json:"expired"`
+}
+
+const (
+	DirectionIncoming = "incoming"
+	DirectionOutgoing = "outgoing"
+)
+
+// TransactionFilter selects a page of a user's transactions, newest first.
+// Before is the ID of the last transaction of the previous page.
+type TransactionFilter struct {
+	Username     string
+	Direction    string
+	Counterparty string
+	From         *time.Time
+	To           *time.Time
+	Before       int
+	Limit        int
+}
+
+type TransactionPage struct {
+	Transactions []CoinTransaction `json:"transactions"`
+	NextCursor   string            `json:"nextCursor,omitempty"`
+}
+
+// Reversal is the outcome of undoing a transfer. When the recipient no longer
+// holds the coins a partial reversal takes what is left and reports the
+// shortfall.
+type Reversal struct {
+	TransactionID int    `json:"transactionId"`
+	ReversalID    int    `json:"reversalId"`
+	Amount        int    `json:"amount"`
+	Shortfall     int    `json:"shortfall"`
+	Reason        string `json:"reason"`
+	ReversedBy    string `json:"reversedBy"`
 }
 
 type CoinHistory struct {
 	Received []ReceivedTransaction `json:"received"`
 	Sent     []SentTransaction     `json:"sent"`
+	Expired  []ExpiredCoins        `json:"expired,omitempty"`
 }
@@ -3,4 +3,5 @@ package entity
 type User struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	IsAdmin  bool   `json:"-"`
 }
@@ -9,6 +9,7 @@ import (
 
 	e "avito-shop/pkg/errors"
 	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
 )
 
 type BalanceRepo struct {
@@ -26,11 +27,17 @@ type Balance interface {
 	GetUserBalance(ctx context.Context, username string) (int, error)
 	DecreaseBalance(ctx context.Context, username string, amount int) error
 	IncreaseBalance(ctx context.Context, username string, amount int) error
+	LockBalances(ctx context.Context, usernames ...string) (map[string]int, error)
+	ListBalances(ctx context.Context) (map[string]int, error)
+	SetBalance(ctx context.Context, username string, coins int) error
 }
 
 func (r *BalanceRepo) InitBalance(ctx context.Context, username string, amount int) error {
 	const op = "repository.balance.InitBalance"
 
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
 	query, args, err := sq.Insert("balance").
 		Columns("username", "coins").
 		Values(username, amount).
@@ -53,6 +60,9 @@ func (r *BalanceRepo) InitBalance(ctx context.Context, username string, amount i
 func (r *BalanceRepo) GetUserBalance(ctx context.Context, username string) (int, error) {
 	const op = "repository.balance.GetUserBalance"
 
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
 	var balance int
 
 	query, args, err := sq.Select("coins").
@@ -89,12 +99,77 @@ func (r *BalanceRepo) GetUserBalance(ctx context.Context, username string) (int,
 	return balance, nil
 }
 
+// LockBalances locks the balance rows of all given users with SELECT ... FOR
+// UPDATE and returns their coins. Rows are always locked in username order, so
+// two transactions touching the same users cannot deadlock each other. Missing
+// users are reported as ErrNotFound.
+func (r *BalanceRepo) LockBalances(ctx context.Context, usernames ...string) (map[string]int, error) {
+	const op = "repository.balance.LockBalances"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("username", "coins").
+		From("balance").
+		Where(sq.Eq{"username": usernames}).
+		OrderBy("username").
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	balances := make(map[string]int, len(usernames))
+
+	for rows.Next() {
+		var (
+			username string
+			coins    int
+		)
+
+		if err = rows.Scan(&username, &coins); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		balances[username] = coins
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, username := range usernames {
+		if _, ok := balances[username]; !ok {
+			return nil, fmt.Errorf("%s: %s: %w", op, username, e.ErrNotFound)
+		}
+	}
+
+	return balances, nil
+}
+
+// DecreaseBalance debits the user only if the balance covers the amount.
+// A debit that would go negative changes nothing and returns
+// ErrInsufficientFunds.
 func (r *BalanceRepo) DecreaseBalance(ctx context.Context, username string, amount int) error {
 	const op = "repository.balance.DecreaseBalance"
 
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
 	query, args, err := sq.Update("balance").
 		Set("coins", sq.Expr("coins - ?", amount)).
 		Where(sq.Eq{"username": username}).
+		Where(sq.GtOrEq{"coins": amount}).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
@@ -103,17 +178,24 @@ func (r *BalanceRepo) DecreaseBalance(ctx context.Context, username string, amou
 
 	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
 
-	_, err = conn.Exec(ctx, query, args...)
+	tag, err := conn.Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, e.ErrInsufficientFunds)
+	}
+
 	return nil
 }
 
 func (r *BalanceRepo) IncreaseBalance(ctx context.Context, username string, amount int) error {
 	const op = "repository.balance.IncreaseBalance"
 
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
 	query, args, err := sq.Update("balance").
 		Set("coins", sq.Expr("coins + ?", amount)).
 		Where(sq.Eq{"username": username}).
@@ -132,3 +214,73 @@ func (r *BalanceRepo) IncreaseBalance(ctx context.Context, username string, amou
 
 	return nil
 }
+
+func (r *BalanceRepo) ListBalances(ctx context.Context) (map[string]int, error) {
+	const op = "repository.balance.ListBalances"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("username", "coins").
+		From("balance").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	balances := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			username string
+			coins    int
+		)
+
+		if err = rows.Scan(&username, &coins); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		balances[username] = coins
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return balances, nil
+}
+
+func (r *BalanceRepo) SetBalance(ctx context.Context, username string, coins int) error {
+	const op = "repository.balance.SetBalance"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Update("balance").
+		Set("coins", coins).
+		Where(sq.Eq{"username": username}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
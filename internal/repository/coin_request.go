@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
+
+	"avito-shop/internal/entity"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
+)
+
+type CoinRequestRepo struct {
+	*postgres.Postgres
+}
+
+func NewCoinRequestRepo(pg *postgres.Postgres) *CoinRequestRepo {
+	return &CoinRequestRepo{pg}
+}
+
+//go:generate mockery --name=CoinRequest
+
+type CoinRequest interface {
+	Add(ctx context.Context, req entity.CoinRequest) (*entity.CoinRequest, error)
+	GetForUpdate(ctx context.Context, id int) (*entity.CoinRequest, error)
+	ListByPayer(ctx context.Context, username string) ([]entity.CoinRequest, error)
+	ListByRequester(ctx context.Context, username string) ([]entity.CoinRequest, error)
+	Resolve(ctx context.Context, id int, status string, resolvedAt time.Time) error
+}
+
+var coinRequestColumns = []string{
+	"id", "requester", "payer", "amount", "note", "status", "createdAt", "expiresAt", "resolvedAt",
+}
+
+func (r *CoinRequestRepo) Add(ctx context.Context, req entity.CoinRequest) (*entity.CoinRequest, error) {
+	const op = "repository.coinRequest.Add"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Insert("coinRequest").
+		Columns("requester", "payer", "amount", "note", "status", "expiresAt").
+		Values(req.Requester, req.Payer, req.Amount, req.Note, req.Status, req.ExpiresAt).
+		Suffix("RETURNING id, createdAt").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	err = conn.QueryRow(ctx, query, args...).Scan(&req.ID, &req.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &req, nil
+}
+
+// GetForUpdate locks the request row so that concurrent accept and decline
+// calls are serialized.
+func (r *CoinRequestRepo) GetForUpdate(ctx context.Context, id int) (*entity.CoinRequest, error) {
+	const op = "repository.coinRequest.GetForUpdate"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select(coinRequestColumns...).
+		From("coinRequest").
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	req, err := scanCoinRequest(conn.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return req, nil
+}
+
+func (r *CoinRequestRepo) ListByPayer(ctx context.Context, username string) ([]entity.CoinRequest, error) {
+	const op = "repository.coinRequest.ListByPayer"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	return r.list(ctx, op, sq.Eq{"payer": username})
+}
+
+func (r *CoinRequestRepo) ListByRequester(ctx context.Context, username string) ([]entity.CoinRequest, error) {
+	const op = "repository.coinRequest.ListByRequester"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	return r.list(ctx, op, sq.Eq{"requester": username})
+}
+
+func (r *CoinRequestRepo) Resolve(ctx context.Context, id int, status string, resolvedAt time.Time) error {
+	const op = "repository.coinRequest.Resolve"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Update("coinRequest").
+		Set("status", status).
+		Set("resolvedAt", resolvedAt).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *CoinRequestRepo) list(ctx context.Context, op string, where sq.Eq) ([]entity.CoinRequest, error) {
+	query, args, err := sq.Select(coinRequestColumns...).
+		From("coinRequest").
+		Where(where).
+		OrderBy("createdAt DESC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var requests []entity.CoinRequest
+
+	for rows.Next() {
+		req, err := scanCoinRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		requests = append(requests, *req)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return requests, nil
+}
+
+func scanCoinRequest(row pgx.Row) (*entity.CoinRequest, error) {
+	var req entity.CoinRequest
+
+	err := row.Scan(
+		&req.ID, &req.Requester, &req.Payer, &req.Amount, &req.Note,
+		&req.Status, &req.CreatedAt, &req.ExpiresAt, &req.ResolvedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+
+	"avito-shop/internal/entity"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
+)
+
+type GrantRepo struct {
+	*postgres.Postgres
+}
+
+func NewGrantRepo(pg *postgres.Postgres) *GrantRepo {
+	return &GrantRepo{pg}
+}
+
+//go:generate mockery --name=Grant
+
+type Grant interface {
+	Add(ctx context.Context, g entity.CoinGrant) (*entity.CoinGrant, error)
+	ListByUser(ctx context.Context, username string) ([]entity.CoinGrant, error)
+}
+
+func (r *GrantRepo) Add(ctx context.Context, g entity.CoinGrant) (*entity.CoinGrant, error) {
+	const op = "repository.grant.Add"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Insert("coinGrant").
+		Columns("username", "amount", "reason", "author").
+		Values(g.Username, g.Amount, g.Reason, g.Author).
+		Suffix("RETURNING id, createdAt").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&g.ID, &g.CreatedAt); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &g, nil
+}
+
+func (r *GrantRepo) ListByUser(ctx context.Context, username string) ([]entity.CoinGrant, error) {
+	const op = "repository.grant.ListByUser"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("id", "username", "amount", "reason", "author", "createdAt").
+		From("coinGrant").
+		Where(sq.Eq{"username": username}).
+		OrderBy("id DESC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var grants []entity.CoinGrant
+
+	for rows.Next() {
+		var g entity.CoinGrant
+
+		if err = rows.Scan(&g.ID, &g.Username, &g.Amount, &g.Reason, &g.Author, &g.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		grants = append(grants, g)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return grants, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
+)
+
+type HealthRepo struct {
+	*postgres.Postgres
+}
+
+func NewHealthRepo(pg *postgres.Postgres) *HealthRepo {
+	return &HealthRepo{pg}
+}
+
+//go:generate mockery --name=Health
+
+type Health interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (uint, bool, error)
+}
+
+// Ping acquires a connection from the pool and round-trips to Postgres. It
+// ignores the transaction in ctx on purpose: a probe checks the pool.
+func (r *HealthRepo) Ping(ctx context.Context) error {
+	const op = "repository.health.Ping"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	if err := r.Pool.Ping(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MigrationVersion returns the version golang-migrate recorded and whether
+// the migration that set it failed half-way.
+func (r *HealthRepo) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	const op = "repository.health.MigrationVersion"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("version", "dirty").
+		From("schema_migrations").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, false, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var (
+		version int64
+		dirty   bool
+	)
+
+	err = r.Pool.QueryRow(ctx, query, args...).Scan(&version, &dirty)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, fmt.Errorf("%s: %w", op, e.ErrNotFound)
+		}
+
+		return 0, false, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return uint(version), dirty, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+
+	"avito-shop/internal/entity"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
+)
+
+type IdempotencyRepo struct {
+	*postgres.Postgres
+}
+
+func NewIdempotencyRepo(pg *postgres.Postgres) *IdempotencyRepo {
+	return &IdempotencyRepo{pg}
+}
+
+//go:generate mockery --name=Idempotency
+
+type Idempotency interface {
+	Reserve(ctx context.Context, key entity.IdempotencyKey) (bool, error)
+	Get(ctx context.Context, username, key string) (*entity.IdempotencyKey, error)
+	Complete(ctx context.Context, key entity.IdempotencyKey) error
+}
+
+// Reserve inserts an empty record for the key. Concurrent reservations of the
+// same key wait on the primary key until the first transaction finishes, so
+// false means the key was already stored by a committed request.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, key entity.IdempotencyKey) (bool, error) {
+	const op = "repository.idempotency.Reserve"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Insert("idempotencyKey").
+		Columns("username", "key", "requestHash", "statusCode", "response").
+		Values(key.Username, key.Key, key.RequestHash, 0, []byte{}).
+		Suffix("ON CONFLICT (username, key) DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *IdempotencyRepo) Get(ctx context.Context, username, key string) (*entity.IdempotencyKey, error) {
+	const op = "repository.idempotency.Get"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("username", "key", "requestHash", "statusCode", "response").
+		From("idempotencyKey").
+		Where(sq.Eq{"username": username, "key": key}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	}
+
+	var record entity.IdempotencyKey
+
+	err = rows.Scan(&record.Username, &record.Key, &record.RequestHash, &record.StatusCode, &record.Response)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &record, nil
+}
+
+func (r *IdempotencyRepo) Complete(ctx context.Context, key entity.IdempotencyKey) error {
+	const op = "repository.idempotency.Complete"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Update("idempotencyKey").
+		Set("statusCode", key.StatusCode).
+		Set("response", key.Response).
+		Where(sq.Eq{"username": key.Username, "key": key.Key}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	_, err = conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
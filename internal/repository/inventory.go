@@ -10,6 +10,7 @@ import (
 	"avito-shop/internal/entity"
 	e "avito-shop/pkg/errors"
 	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
 )
 
 type InventoryRepo struct {
@@ -33,6 +34,9 @@ type Inventory interface {
 func (r *InventoryRepo) GetItemPrice(ctx context.Context, name string) (int, error) {
 	const op = "repository.inventory.GetItemPrice"
 
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
 	var price int
 
 	query, args, err := sq.Select("price").
@@ -71,6 +75,9 @@ func (r *InventoryRepo) GetItemPrice(ctx context.Context, name string) (int, err
 func (r *InventoryRepo) ExistsInventoryItem(ctx context.Context, username, item string) (bool, error) {
 	const op = "repository.inventory.ExistsInventoryItem"
 
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
 	query, _, err := sq.Select("EXISTS(SELECT 1 FROM inventory WHERE username = $1 AND item = $2)").
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
@@ -93,6 +100,9 @@ func (r *InventoryRepo) ExistsInventoryItem(ctx context.Context, username, item
 func (r *InventoryRepo) IncrementInventoryItemQuantity(ctx context.Context, username, item string) error {
 	const op = "repository.inventory.IncrementInventoryItemQuantity"
 
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
 	query, args, err := sq.Update("inventory").
 		Set("quantity", sq.Expr("quantity + 1")).
 		Where(sq.Eq{"username": username, "item": item}).
@@ -115,6 +125,9 @@ func (r *InventoryRepo) IncrementInventoryItemQuantity(ctx context.Context, user
 func (r *InventoryRepo) AddInventory(ctx context.Context, inventory entity.Inventory) error {
 	const op = "repository.inventory.AddInventory"
 
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
 	query, args, err := sq.Insert("inventory").
 		Columns("username", "item", "quantity").
 		Values(inventory.Username, inventory.Item, inventory.Quantity).
@@ -137,6 +150,9 @@ func (r *InventoryRepo) AddInventory(ctx context.Context, inventory entity.Inven
 func (r *InventoryRepo) GetInventory(ctx context.Context, username string) ([]entity.InventoryItem, error) {
 	const op = "repository.inventory.getInventory"
 
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
 	query, args, err := sq.Select("item, quantity").
 		From("inventory").
 		Where(sq.Eq{"username": username}).
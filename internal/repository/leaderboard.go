@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+
+	"avito-shop/internal/entity"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
+)
+
+type LeaderboardRepo struct {
+	*postgres.Postgres
+}
+
+func NewLeaderboardRepo(pg *postgres.Postgres) *LeaderboardRepo {
+	return &LeaderboardRepo{pg}
+}
+
+//go:generate mockery --name=Leaderboard
+
+type Leaderboard interface {
+	TopReceivers(ctx context.Context, since *time.Time, limit int) ([]entity.UserScore, error)
+	TopSenders(ctx context.Context, since *time.Time, limit int) ([]entity.UserScore, error)
+	MostGenerous(ctx context.Context, since *time.Time, limit int) ([]entity.UserScore, error)
+	MostBoughtItems(ctx context.Context, since *time.Time, limit int) ([]entity.ItemScore, error)
+	SetOptOut(ctx context.Context, username string, optOut bool) error
+}
+
+// TopReceivers ranks users by coins received since the given time.
+func (r *LeaderboardRepo) TopReceivers(ctx context.Context, since *time.Time, limit int) ([]entity.UserScore, error) {
+	const op = "repository.leaderboard.TopReceivers"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	scores, err := r.userScores(ctx, "toUser", "COUNT(*)", since, limit, "SUM(amount) DESC")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return scores, nil
+}
+
+// TopSenders ranks users by coins sent since the given time.
+func (r *LeaderboardRepo) TopSenders(ctx context.Context, since *time.Time, limit int) ([]entity.UserScore, error) {
+	const op = "repository.leaderboard.TopSenders"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	scores, err := r.userScores(ctx, "fromUser", "COUNT(*)", since, limit, "SUM(amount) DESC")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return scores, nil
+}
+
+// MostGenerous ranks senders by how many different colleagues they sent coins to.
+func (r *LeaderboardRepo) MostGenerous(ctx context.Context, since *time.Time, limit int) ([]entity.UserScore, error) {
+	const op = "repository.leaderboard.MostGenerous"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	scores, err := r.userScores(ctx, "fromUser", "COUNT(DISTINCT toUser)", since, limit,
+		"COUNT(DISTINCT toUser) DESC", "SUM(amount) DESC")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return scores, nil
+}
+
+// userScores groups completed transfers by the user column and ranks them by
+// orderBy. Reversals and reversed transfers are left out, as are users who
+// opted out.
+func (r *LeaderboardRepo) userScores(
+	ctx context.Context,
+	column, count string,
+	since *time.Time,
+	limit int,
+	orderBy ...string,
+) ([]entity.UserScore, error) {
+	builder := sq.Select(column, "SUM(amount)", count).
+		From("coinTransaction").
+		Where(sq.Eq{"reversalOf": nil, "reversedAt": nil}).
+		Where("NOT EXISTS (SELECT 1 FROM users u WHERE u.username = " + column + " AND u.leaderboardOptOut)")
+
+	if since != nil {
+		builder = builder.Where(sq.GtOrEq{"createdAt": *since})
+	}
+
+	query, args, err := builder.
+		GroupBy(column).
+		OrderBy(append(orderBy, column)...).
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	defer rows.Close()
+
+	scores := []entity.UserScore{}
+
+	for rows.Next() {
+		var s entity.UserScore
+		if err = rows.Scan(&s.Username, &s.Coins, &s.Count); err != nil {
+			return nil, err
+		}
+
+		scores = append(scores, s)
+	}
+
+	return scores, rows.Err()
+}
+
+// MostBoughtItems ranks merch by purchases recorded in the ledger since the
+// given time. Purchases made before the ledger existed are not counted.
+func (r *LeaderboardRepo) MostBoughtItems(ctx context.Context, since *time.Time, limit int) ([]entity.ItemScore, error) {
+	const op = "repository.leaderboard.MostBoughtItems"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	builder := sq.Select("reference", "COUNT(*)").
+		From("ledgerEntry").
+		Where(sq.Eq{"kind": entity.LedgerKindPurchase})
+
+	if since != nil {
+		builder = builder.Where(sq.GtOrEq{"createdAt": *since})
+	}
+
+	query, args, err := builder.
+		GroupBy("reference").
+		OrderBy("COUNT(*) DESC", "reference").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	scores := []entity.ItemScore{}
+
+	for rows.Next() {
+		var s entity.ItemScore
+		if err = rows.Scan(&s.Item, &s.Count); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		scores = append(scores, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return scores, nil
+}
+
+// SetOptOut hides the user from, or returns them to, the leaderboards.
+func (r *LeaderboardRepo) SetOptOut(ctx context.Context, username string, optOut bool) error {
+	const op = "repository.leaderboard.SetOptOut"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Update("users").
+		Set("leaderboardOptOut", optOut).
+		Where(sq.Eq{"username": username}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	}
+
+	return nil
+}
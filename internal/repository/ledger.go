@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+
+	"avito-shop/internal/entity"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
+)
+
+type LedgerRepo struct {
+	*postgres.Postgres
+}
+
+func NewLedgerRepo(pg *postgres.Postgres) *LedgerRepo {
+	return &LedgerRepo{pg}
+}
+
+//go:generate mockery --name=Ledger
+
+type Ledger interface {
+	Record(ctx context.Context, entry entity.LedgerEntry) error
+	UserBalances(ctx context.Context) (map[string]int, error)
+	StreamStatement(ctx context.Context, f entity.StatementFilter, fn func(entity.StatementLine) error) error
+}
+
+// Record stores the entry with its postings. Unbalanced entries are rejected
+// here and, as a backstop, by a deferred constraint trigger at commit.
+func (r *LedgerRepo) Record(ctx context.Context, entry entity.LedgerEntry) error {
+	const op = "repository.ledger.Record"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	if !entry.Balanced() {
+		return fmt.Errorf("%s: %w", op, e.ErrUnbalancedEntry)
+	}
+
+	query, args, err := sq.Insert("ledgerEntry").
+		Columns("kind", "reference").
+		Values(entry.Kind, entry.Reference).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	var entryID int
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&entryID); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	insert := sq.Insert("ledgerPosting").Columns("entryID", "account", "amount")
+	for _, p := range entry.Postings {
+		insert = insert.Values(entryID, p.Account, p.Amount)
+	}
+
+	query, args, err = insert.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err = conn.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// UserBalances sums the postings of every user account.
+func (r *LedgerRepo) UserBalances(ctx context.Context) (map[string]int, error) {
+	const op = "repository.ledger.UserBalances"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("account", "SUM(amount)").
+		From("ledgerPosting").
+		Where(sq.Like{"account": entity.UserAccount("%")}).
+		GroupBy("account").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	balances := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			account string
+			coins   int
+		)
+
+		if err = rows.Scan(&account, &coins); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if username, ok := entity.AccountUser(account); ok {
+			balances[username] = coins
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return balances, nil
+}
+
+// StreamStatement calls fn for every posting on the user's account in entry
+// order, without loading the statement into memory. The running balance is
+// summed over the whole history, so it is correct for a bounded period too.
+// Counterparty is the other account of the entry.
+func (r *LedgerRepo) StreamStatement(
+	ctx context.Context,
+	f entity.StatementFilter,
+	fn func(entity.StatementLine) error,
+) error {
+	const op = "repository.ledger.StreamStatement"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	postings := sq.Select(
+		"e.id", "e.kind", "e.reference", "e.createdAt", "p.account", "p.amount",
+		"SUM(p.amount) OVER (ORDER BY e.id) AS balance",
+	).
+		From("ledgerPosting p").
+		Join("ledgerEntry e ON e.id = p.entryID").
+		Where(sq.Eq{"p.account": entity.UserAccount(f.Username)})
+
+	builder := sq.Select(
+		"s.id", "s.createdAt", "s.kind",
+		"COALESCE((SELECT o.account FROM ledgerPosting o "+
+			"WHERE o.entryID = s.id AND o.account <> s.account ORDER BY o.id LIMIT 1), '')",
+		"s.reference", "s.amount", "s.balance",
+	).
+		FromSelect(postings, "s")
+
+	if f.From != nil {
+		builder = builder.Where(sq.GtOrEq{"s.createdAt": *f.From})
+	}
+
+	if f.To != nil {
+		builder = builder.Where(sq.Lt{"s.createdAt": *f.To})
+	}
+
+	query, args, err := builder.OrderBy("s.id").PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var line entity.StatementLine
+
+		err = rows.Scan(&line.EntryID, &line.CreatedAt, &line.Kind,
+			&line.Counterparty, &line.Reference, &line.Amount, &line.Balance)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = fn(line); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
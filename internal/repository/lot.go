@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
+
+	"avito-shop/internal/entity"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
+)
+
+type LotRepo struct {
+	*postgres.Postgres
+}
+
+func NewLotRepo(pg *postgres.Postgres) *LotRepo {
+	return &LotRepo{pg}
+}
+
+//go:generate mockery --name=Lot
+
+type Lot interface {
+	AddLot(ctx context.Context, username, source string, amount int) error
+	ConsumeLots(ctx context.Context, username string, amount int) error
+	NextExpiredUser(ctx context.Context, cutoff time.Time) (string, error)
+	ExpireLots(ctx context.Context, username string, cutoff, now time.Time, limit int) (int, error)
+	ListExpiries(ctx context.Context, username string) ([]entity.ExpiredCoins, error)
+	GetExpirySummary(ctx context.Context, username string) (entity.ExpirySummary, error)
+}
+
+func (r *LotRepo) AddLot(ctx context.Context, username, source string, amount int) error {
+	const op = "repository.lot.AddLot"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Insert("coinLot").
+		Columns("username", "source", "amount", "remaining").
+		Values(username, source, amount, amount).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	if _, err = conn.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// ConsumeLots takes amount coins from the oldest lots of the user. The balance
+// check is done on Balance; lots only drive expiry, so a shortfall left by a
+// manual balance correction is not an error.
+func (r *LotRepo) ConsumeLots(ctx context.Context, username string, amount int) error {
+	const op = "repository.lot.ConsumeLots"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("id", "remaining").
+		From("coinLot").
+		Where(sq.Eq{"username": username}).
+		Where(sq.Gt{"remaining": 0}).
+		OrderBy("receivedAt", "id").
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	// Collect first: the connection is busy until rows are closed.
+	taken := make(map[int]int)
+
+	for rows.Next() && amount > 0 {
+		var id, remaining int
+
+		if err = rows.Scan(&id, &remaining); err != nil {
+			rows.Close()
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		take := min(remaining, amount)
+		taken[id] = take
+		amount -= take
+	}
+
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for id, take := range taken {
+		query, args, err = sq.Update("coinLot").
+			Set("remaining", sq.Expr("remaining - ?", take)).
+			Where(sq.Eq{"id": id}).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("%s: failed to build query: %w", op, err)
+		}
+
+		if _, err = conn.Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("%s: failed to execute query: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+// NextExpiredUser returns the user holding the oldest coins received before
+// cutoff and locks their balance row. Balances locked by another expiry run or
+// a transfer are skipped, so instances share the work and a user that is
+// stuck does not hold up the rest; the lock order matches transfers.
+func (r *LotRepo) NextExpiredUser(ctx context.Context, cutoff time.Time) (string, error) {
+	const op = "repository.lot.NextExpiredUser"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("l.username").
+		From("coinLot l").
+		Join("balance b ON b.username = l.username").
+		Where(sq.LtOrEq{"l.receivedAt": cutoff}).
+		Where(sq.Gt{"l.remaining": 0}).
+		OrderBy("l.receivedAt", "l.id").
+		Limit(1).
+		Suffix("FOR UPDATE OF b SKIP LOCKED").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	var username string
+
+	err = conn.QueryRow(ctx, query, args...).Scan(&username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	} else if err != nil {
+		return "", fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return username, nil
+}
+
+// ExpireLots expires the user's lots received before cutoff, oldest first, up
+// to limit coins, records an expiry event with the amount taken from each lot
+// and returns the total. limit is the balance: whatever the due lots hold
+// beyond it is no longer in the balance (it was corrected by hand), so it is
+// written off without an expiry event and the lots end up empty either way.
+func (r *LotRepo) ExpireLots(ctx context.Context, username string, cutoff, now time.Time, limit int) (int, error) {
+	const op = "repository.lot.ExpireLots"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("id", "remaining").
+		From("coinLot").
+		Where(sq.Eq{"username": username}).
+		Where(sq.LtOrEq{"receivedAt": cutoff}).
+		Where(sq.Gt{"remaining": 0}).
+		OrderBy("receivedAt", "id").
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	ids := make([]int, 0)
+	expiries := sq.Insert("coinExpiry").Columns("lotID", "username", "amount", "expiredAt")
+	total := 0
+
+	for rows.Next() {
+		var id, remaining int
+
+		if err = rows.Scan(&id, &remaining); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		ids = append(ids, id)
+
+		if take := min(remaining, limit-total); take > 0 {
+			expiries = expiries.Values(id, username, take, now)
+			total += take
+		}
+	}
+
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query, args, err = sq.Update("coinLot").
+		Set("remaining", 0).
+		Where(sq.Eq{"id": ids}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err = conn.Exec(ctx, query, args...); err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	if total == 0 {
+		return 0, nil
+	}
+
+	query, args, err = expiries.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err = conn.Exec(ctx, query, args...); err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return total, nil
+}
+
+func (r *LotRepo) ListExpiries(ctx context.Context, username string) ([]entity.ExpiredCoins, error) {
+	const op = "repository.lot.ListExpiries"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("amount", "expiredAt").
+		From("coinExpiry").
+		Where(sq.Eq{"username": username}).
+		OrderBy("id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var expiries []entity.ExpiredCoins
+
+	for rows.Next() {
+		var ex entity.ExpiredCoins
+
+		if err = rows.Scan(&ex.Amount, &ex.ExpiredAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		expiries = append(expiries, ex)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return expiries, nil
+}
+
+// GetExpirySummary totals the coins of the user that expired.
+func (r *LotRepo) GetExpirySummary(ctx context.Context, username string) (entity.ExpirySummary, error) {
+	const op = "repository.lot.GetExpirySummary"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("COALESCE(SUM(amount), 0)", "COUNT(*)").
+		From("coinExpiry").
+		Where(sq.Eq{"username": username}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return entity.ExpirySummary{}, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	var summary entity.ExpirySummary
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&summary.Amount, &summary.Count); err != nil {
+		return entity.ExpirySummary{}, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return summary, nil
+}
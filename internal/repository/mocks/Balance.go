@@ -95,6 +95,91 @@ func (_m *Balance) InitBalance(ctx context.Context, username string, amount int)
 	return r0
 }
 
+// ListBalances provides a mock function with given fields: ctx
+func (_m *Balance) ListBalances(ctx context.Context) (map[string]int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBalances")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string]int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]int); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockBalances provides a mock function with given fields: ctx, usernames
+func (_m *Balance) LockBalances(ctx context.Context, usernames ...string) (map[string]int, error) {
+	_va := make([]interface{}, len(usernames))
+	for _i := range usernames {
+		_va[_i] = usernames[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockBalances")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) (map[string]int, error)); ok {
+		return rf(ctx, usernames...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) map[string]int); ok {
+		r0 = rf(ctx, usernames...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ...string) error); ok {
+		r1 = rf(ctx, usernames...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetBalance provides a mock function with given fields: ctx, username, coins
+func (_m *Balance) SetBalance(ctx context.Context, username string, coins int) error {
+	ret := _m.Called(ctx, username, coins)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetBalance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, username, coins)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewBalance creates a new instance of Balance. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBalance(t interface {
@@ -0,0 +1,169 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// CoinRequest is an autogenerated mock type for the CoinRequest type
+type CoinRequest struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: ctx, req
+func (_m *CoinRequest) Add(ctx context.Context, req entity.CoinRequest) (*entity.CoinRequest, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Add")
+	}
+
+	var r0 *entity.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CoinRequest) (*entity.CoinRequest, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CoinRequest) *entity.CoinRequest); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.CoinRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetForUpdate provides a mock function with given fields: ctx, id
+func (_m *CoinRequest) GetForUpdate(ctx context.Context, id int) (*entity.CoinRequest, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForUpdate")
+	}
+
+	var r0 *entity.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.CoinRequest, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.CoinRequest); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByPayer provides a mock function with given fields: ctx, username
+func (_m *CoinRequest) ListByPayer(ctx context.Context, username string) ([]entity.CoinRequest, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByPayer")
+	}
+
+	var r0 []entity.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.CoinRequest, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.CoinRequest); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByRequester provides a mock function with given fields: ctx, username
+func (_m *CoinRequest) ListByRequester(ctx context.Context, username string) ([]entity.CoinRequest, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByRequester")
+	}
+
+	var r0 []entity.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.CoinRequest, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.CoinRequest); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Resolve provides a mock function with given fields: ctx, id, status, resolvedAt
+func (_m *CoinRequest) Resolve(ctx context.Context, id int, status string, resolvedAt time.Time) error {
+	ret := _m.Called(ctx, id, status, resolvedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resolve")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Time) error); ok {
+		r0 = rf(ctx, id, status, resolvedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCoinRequest creates a new instance of CoinRequest. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCoinRequest(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CoinRequest {
+	mock := &CoinRequest{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Grant is an autogenerated mock type for the Grant type
+type Grant struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: ctx, g
+func (_m *Grant) Add(ctx context.Context, g entity.CoinGrant) (*entity.CoinGrant, error) {
+	ret := _m.Called(ctx, g)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Add")
+	}
+
+	var r0 *entity.CoinGrant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CoinGrant) (*entity.CoinGrant, error)); ok {
+		return rf(ctx, g)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CoinGrant) *entity.CoinGrant); ok {
+		r0 = rf(ctx, g)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CoinGrant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.CoinGrant) error); ok {
+		r1 = rf(ctx, g)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByUser provides a mock function with given fields: ctx, username
+func (_m *Grant) ListByUser(ctx context.Context, username string) ([]entity.CoinGrant, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUser")
+	}
+
+	var r0 []entity.CoinGrant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.CoinGrant, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.CoinGrant); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.CoinGrant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewGrant creates a new instance of Grant. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGrant(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Grant {
+	mock := &Grant{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
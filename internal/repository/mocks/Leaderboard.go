@@ -0,0 +1,169 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Leaderboard is an autogenerated mock type for the Leaderboard type
+type Leaderboard struct {
+	mock.Mock
+}
+
+// MostBoughtItems provides a mock function with given fields: ctx, since, limit
+func (_m *Leaderboard) MostBoughtItems(ctx context.Context, since *time.Time, limit int) ([]entity.ItemScore, error) {
+	ret := _m.Called(ctx, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MostBoughtItems")
+	}
+
+	var r0 []entity.ItemScore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time, int) ([]entity.ItemScore, error)); ok {
+		return rf(ctx, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time, int) []entity.ItemScore); ok {
+		r0 = rf(ctx, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ItemScore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *time.Time, int) error); ok {
+		r1 = rf(ctx, since, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MostGenerous provides a mock function with given fields: ctx, since, limit
+func (_m *Leaderboard) MostGenerous(ctx context.Context, since *time.Time, limit int) ([]entity.UserScore, error) {
+	ret := _m.Called(ctx, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MostGenerous")
+	}
+
+	var r0 []entity.UserScore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time, int) ([]entity.UserScore, error)); ok {
+		return rf(ctx, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time, int) []entity.UserScore); ok {
+		r0 = rf(ctx, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.UserScore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *time.Time, int) error); ok {
+		r1 = rf(ctx, since, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetOptOut provides a mock function with given fields: ctx, username, optOut
+func (_m *Leaderboard) SetOptOut(ctx context.Context, username string, optOut bool) error {
+	ret := _m.Called(ctx, username, optOut)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetOptOut")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, username, optOut)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TopReceivers provides a mock function with given fields: ctx, since, limit
+func (_m *Leaderboard) TopReceivers(ctx context.Context, since *time.Time, limit int) ([]entity.UserScore, error) {
+	ret := _m.Called(ctx, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TopReceivers")
+	}
+
+	var r0 []entity.UserScore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time, int) ([]entity.UserScore, error)); ok {
+		return rf(ctx, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time, int) []entity.UserScore); ok {
+		r0 = rf(ctx, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.UserScore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *time.Time, int) error); ok {
+		r1 = rf(ctx, since, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TopSenders provides a mock function with given fields: ctx, since, limit
+func (_m *Leaderboard) TopSenders(ctx context.Context, since *time.Time, limit int) ([]entity.UserScore, error) {
+	ret := _m.Called(ctx, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TopSenders")
+	}
+
+	var r0 []entity.UserScore
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time, int) ([]entity.UserScore, error)); ok {
+		return rf(ctx, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time, int) []entity.UserScore); ok {
+		r0 = rf(ctx, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.UserScore)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *time.Time, int) error); ok {
+		r1 = rf(ctx, since, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLeaderboard creates a new instance of Leaderboard. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLeaderboard(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Leaderboard {
+	mock := &Leaderboard{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Ledger is an autogenerated mock type for the Ledger type
+type Ledger struct {
+	mock.Mock
+}
+
+// Record provides a mock function with given fields: ctx, entry
+func (_m *Ledger) Record(ctx context.Context, entry entity.LedgerEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.LedgerEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StreamStatement provides a mock function with given fields: ctx, f, fn
+func (_m *Ledger) StreamStatement(ctx context.Context, f entity.StatementFilter, fn func(entity.StatementLine) error) error {
+	ret := _m.Called(ctx, f, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamStatement")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.StatementFilter, func(entity.StatementLine) error) error); ok {
+		r0 = rf(ctx, f, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserBalances provides a mock function with given fields: ctx
+func (_m *Ledger) UserBalances(ctx context.Context) (map[string]int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserBalances")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string]int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]int); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLedger creates a new instance of Ledger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLedger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Ledger {
+	mock := &Ledger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
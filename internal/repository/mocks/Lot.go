@@ -0,0 +1,181 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Lot is an autogenerated mock type for the Lot type
+type Lot struct {
+	mock.Mock
+}
+
+// AddLot provides a mock function with given fields: ctx, username, source, amount
+func (_m *Lot) AddLot(ctx context.Context, username string, source string, amount int) error {
+	ret := _m.Called(ctx, username, source, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddLot")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) error); ok {
+		r0 = rf(ctx, username, source, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConsumeLots provides a mock function with given fields: ctx, username, amount
+func (_m *Lot) ConsumeLots(ctx context.Context, username string, amount int) error {
+	ret := _m.Called(ctx, username, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeLots")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, username, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpireLots provides a mock function with given fields: ctx, username, cutoff, now, limit
+func (_m *Lot) ExpireLots(ctx context.Context, username string, cutoff time.Time, now time.Time, limit int) (int, error) {
+	ret := _m.Called(ctx, username, cutoff, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireLots")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, int) (int, error)); ok {
+		return rf(ctx, username, cutoff, now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, int) int); ok {
+		r0 = rf(ctx, username, cutoff, now, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time, int) error); ok {
+		r1 = rf(ctx, username, cutoff, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExpirySummary provides a mock function with given fields: ctx, username
+func (_m *Lot) GetExpirySummary(ctx context.Context, username string) (entity.ExpirySummary, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExpirySummary")
+	}
+
+	var r0 entity.ExpirySummary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entity.ExpirySummary, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entity.ExpirySummary); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(entity.ExpirySummary)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListExpiries provides a mock function with given fields: ctx, username
+func (_m *Lot) ListExpiries(ctx context.Context, username string) ([]entity.ExpiredCoins, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListExpiries")
+	}
+
+	var r0 []entity.ExpiredCoins
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.ExpiredCoins, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.ExpiredCoins); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ExpiredCoins)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NextExpiredUser provides a mock function with given fields: ctx, cutoff
+func (_m *Lot) NextExpiredUser(ctx context.Context, cutoff time.Time) (string, error) {
+	ret := _m.Called(ctx, cutoff)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextExpiredUser")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (string, error)); ok {
+		return rf(ctx, cutoff)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) string); ok {
+		r0 = rf(ctx, cutoff)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, cutoff)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLot creates a new instance of Lot. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLot(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Lot {
+	mock := &Lot{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,167 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// PendingTransfer is an autogenerated mock type for the PendingTransfer type
+type PendingTransfer struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: ctx, pt
+func (_m *PendingTransfer) Add(ctx context.Context, pt entity.PendingTransfer) (*entity.PendingTransfer, error) {
+	ret := _m.Called(ctx, pt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Add")
+	}
+
+	var r0 *entity.PendingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PendingTransfer) (*entity.PendingTransfer, error)); ok {
+		return rf(ctx, pt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PendingTransfer) *entity.PendingTransfer); ok {
+		r0 = rf(ctx, pt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.PendingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.PendingTransfer) error); ok {
+		r1 = rf(ctx, pt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetForUpdate provides a mock function with given fields: ctx, id
+func (_m *PendingTransfer) GetForUpdate(ctx context.Context, id int) (*entity.PendingTransfer, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForUpdate")
+	}
+
+	var r0 *entity.PendingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.PendingTransfer, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.PendingTransfer); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.PendingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPending provides a mock function with given fields: ctx
+func (_m *PendingTransfer) ListPending(ctx context.Context) ([]entity.PendingTransfer, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPending")
+	}
+
+	var r0 []entity.PendingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.PendingTransfer, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.PendingTransfer); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PendingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PendingSince provides a mock function with given fields: ctx, username, since
+func (_m *PendingTransfer) PendingSince(ctx context.Context, username string, since time.Time) (int, error) {
+	ret := _m.Called(ctx, username, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PendingSince")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int, error)); ok {
+		return rf(ctx, username, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int); ok {
+		r0 = rf(ctx, username, since)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, username, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Resolve provides a mock function with given fields: ctx, id, status, resolvedBy, resolvedAt
+func (_m *PendingTransfer) Resolve(ctx context.Context, id int, status string, resolvedBy string, resolvedAt time.Time) error {
+	ret := _m.Called(ctx, id, status, resolvedBy, resolvedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resolve")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, time.Time) error); ok {
+		r0 = rf(ctx, id, status, resolvedBy, resolvedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPendingTransfer creates a new instance of PendingTransfer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPendingTransfer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PendingTransfer {
+	mock := &PendingTransfer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
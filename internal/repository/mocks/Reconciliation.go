@@ -0,0 +1,87 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Reconciliation is an autogenerated mock type for the Reconciliation type
+type Reconciliation struct {
+	mock.Mock
+}
+
+// CheckBalances provides a mock function with given fields: ctx
+func (_m *Reconciliation) CheckBalances(ctx context.Context) ([]entity.BalanceCheck, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckBalances")
+	}
+
+	var r0 []entity.BalanceCheck
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.BalanceCheck, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.BalanceCheck); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.BalanceCheck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveReport provides a mock function with given fields: ctx, report
+func (_m *Reconciliation) SaveReport(ctx context.Context, report entity.DriftReport) (int, error) {
+	ret := _m.Called(ctx, report)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveReport")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.DriftReport) (int, error)); ok {
+		return rf(ctx, report)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.DriftReport) int); ok {
+		r0 = rf(ctx, report)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.DriftReport) error); ok {
+		r1 = rf(ctx, report)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReconciliation creates a new instance of Reconciliation. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReconciliation(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Reconciliation {
+	mock := &Reconciliation{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
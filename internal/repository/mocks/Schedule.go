@@ -0,0 +1,185 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Schedule is an autogenerated mock type for the Schedule type
+type Schedule struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: ctx, st
+func (_m *Schedule) Add(ctx context.Context, st entity.ScheduledTransfer) (int, error) {
+	ret := _m.Called(ctx, st)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Add")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ScheduledTransfer) (int, error)); ok {
+		return rf(ctx, st)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ScheduledTransfer) int); ok {
+		r0 = rf(ctx, st)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ScheduledTransfer) error); ok {
+		r1 = rf(ctx, st)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimDue provides a mock function with given fields: ctx, now
+func (_m *Schedule) ClaimDue(ctx context.Context, now time.Time) (*entity.ScheduledTransfer, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDue")
+	}
+
+	var r0 *entity.ScheduledTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (*entity.ScheduledTransfer, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) *entity.ScheduledTransfer); ok {
+		r0 = rf(ctx, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ScheduledTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *Schedule) Delete(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *Schedule) Get(ctx context.Context, id int) (*entity.ScheduledTransfer, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *entity.ScheduledTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.ScheduledTransfer, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.ScheduledTransfer); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ScheduledTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByUser provides a mock function with given fields: ctx, username
+func (_m *Schedule) ListByUser(ctx context.Context, username string) ([]entity.ScheduledTransfer, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUser")
+	}
+
+	var r0 []entity.ScheduledTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.ScheduledTransfer, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.ScheduledTransfer); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ScheduledTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, st
+func (_m *Schedule) Update(ctx context.Context, st entity.ScheduledTransfer) error {
+	ret := _m.Called(ctx, st)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ScheduledTransfer) error); ok {
+		r0 = rf(ctx, st)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSchedule creates a new instance of Schedule. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSchedule(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Schedule {
+	mock := &Schedule{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
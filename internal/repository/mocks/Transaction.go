@@ -7,6 +7,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Transaction is an autogenerated mock type for the Transaction type
@@ -14,6 +16,34 @@ type Transaction struct {
 	mock.Mock
 }
 
+// AddReversal provides a mock function with given fields: ctx, txn, reversedBy, reversedAt
+func (_m *Transaction) AddReversal(ctx context.Context, txn entity.CoinTransaction, reversedBy string, reversedAt time.Time) (int, error) {
+	ret := _m.Called(ctx, txn, reversedBy, reversedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddReversal")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CoinTransaction, string, time.Time) (int, error)); ok {
+		return rf(ctx, txn, reversedBy, reversedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CoinTransaction, string, time.Time) int); ok {
+		r0 = rf(ctx, txn, reversedBy, reversedAt)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.CoinTransaction, string, time.Time) error); ok {
+		r1 = rf(ctx, txn, reversedBy, reversedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // AddTransaction provides a mock function with given fields: ctx, txn
 func (_m *Transaction) AddTransaction(ctx context.Context, txn entity.CoinTransaction) error {
 	ret := _m.Called(ctx, txn)
@@ -32,24 +62,54 @@ func (_m *Transaction) AddTransaction(ctx context.Context, txn entity.CoinTransa
 	return r0
 }
 
-// GetReceivedTransactions provides a mock function with given fields: ctx, username
-func (_m *Transaction) GetReceivedTransactions(ctx context.Context, username string) ([]entity.ReceivedTransaction, error) {
+// GetForUpdate provides a mock function with given fields: ctx, id
+func (_m *Transaction) GetForUpdate(ctx context.Context, id int) (*entity.CoinTransaction, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForUpdate")
+	}
+
+	var r0 *entity.CoinTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.CoinTransaction, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.CoinTransaction); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CoinTransaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReceivedSummary provides a mock function with given fields: ctx, username
+func (_m *Transaction) GetReceivedSummary(ctx context.Context, username string) ([]entity.ReceivedSummary, error) {
 	ret := _m.Called(ctx, username)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetReceivedTransactions")
+		panic("no return value specified for GetReceivedSummary")
 	}
 
-	var r0 []entity.ReceivedTransaction
+	var r0 []entity.ReceivedSummary
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.ReceivedTransaction, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.ReceivedSummary, error)); ok {
 		return rf(ctx, username)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.ReceivedTransaction); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.ReceivedSummary); ok {
 		r0 = rf(ctx, username)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]entity.ReceivedTransaction)
+			r0 = ret.Get(0).([]entity.ReceivedSummary)
 		}
 	}
 
@@ -62,24 +122,54 @@ func (_m *Transaction) GetReceivedTransactions(ctx context.Context, username str
 	return r0, r1
 }
 
-// GetSentTransactions provides a mock function with given fields: ctx, username
-func (_m *Transaction) GetSentTransactions(ctx context.Context, username string) ([]entity.SentTransaction, error) {
+// GetReceivedTransactions provides a mock function with given fields: ctx, username, limit
+func (_m *Transaction) GetReceivedTransactions(ctx context.Context, username string, limit int) ([]entity.ReceivedTransaction, error) {
+	ret := _m.Called(ctx, username, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReceivedTransactions")
+	}
+
+	var r0 []entity.ReceivedTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]entity.ReceivedTransaction, error)); ok {
+		return rf(ctx, username, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []entity.ReceivedTransaction); ok {
+		r0 = rf(ctx, username, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ReceivedTransaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, username, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSentSummary provides a mock function with given fields: ctx, username
+func (_m *Transaction) GetSentSummary(ctx context.Context, username string) ([]entity.SentSummary, error) {
 	ret := _m.Called(ctx, username)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetSentTransactions")
+		panic("no return value specified for GetSentSummary")
 	}
 
-	var r0 []entity.SentTransaction
+	var r0 []entity.SentSummary
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.SentTransaction, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.SentSummary, error)); ok {
 		return rf(ctx, username)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.SentTransaction); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.SentSummary); ok {
 		r0 = rf(ctx, username)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]entity.SentTransaction)
+			r0 = ret.Get(0).([]entity.SentSummary)
 		}
 	}
 
@@ -92,6 +182,94 @@ func (_m *Transaction) GetSentTransactions(ctx context.Context, username string)
 	return r0, r1
 }
 
+// GetSentTransactions provides a mock function with given fields: ctx, username, limit
+func (_m *Transaction) GetSentTransactions(ctx context.Context, username string, limit int) ([]entity.SentTransaction, error) {
+	ret := _m.Called(ctx, username, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSentTransactions")
+	}
+
+	var r0 []entity.SentTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]entity.SentTransaction, error)); ok {
+		return rf(ctx, username, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []entity.SentTransaction); ok {
+		r0 = rf(ctx, username, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.SentTransaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, username, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTransactions provides a mock function with given fields: ctx, f
+func (_m *Transaction) ListTransactions(ctx context.Context, f entity.TransactionFilter) ([]entity.CoinTransaction, error) {
+	ret := _m.Called(ctx, f)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransactions")
+	}
+
+	var r0 []entity.CoinTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.TransactionFilter) ([]entity.CoinTransaction, error)); ok {
+		return rf(ctx, f)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.TransactionFilter) []entity.CoinTransaction); ok {
+		r0 = rf(ctx, f)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.CoinTransaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.TransactionFilter) error); ok {
+		r1 = rf(ctx, f)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SentSince provides a mock function with given fields: ctx, username, since
+func (_m *Transaction) SentSince(ctx context.Context, username string, since time.Time) (int, error) {
+	ret := _m.Called(ctx, username, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SentSince")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int, error)); ok {
+		return rf(ctx, username, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int); ok {
+		r0 = rf(ctx, username, since)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, username, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewTransaction creates a new instance of Transaction. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTransaction(t interface {
@@ -62,6 +62,24 @@ func (_m *User) Get(ctx context.Context, username string) (*entity.User, error)
 	return r0, r1
 }
 
+// SetAdmin provides a mock function with given fields: ctx, username, admin
+func (_m *User) SetAdmin(ctx context.Context, username string, admin bool) error {
+	ret := _m.Called(ctx, username, admin)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAdmin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, username, admin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUser creates a new instance of User. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUser(t interface {
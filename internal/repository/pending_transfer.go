@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
+
+	"avito-shop/internal/entity"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
+)
+
+type PendingTransferRepo struct {
+	*postgres.Postgres
+}
+
+func NewPendingTransferRepo(pg *postgres.Postgres) *PendingTransferRepo {
+	return &PendingTransferRepo{pg}
+}
+
+//go:generate mockery --name=PendingTransfer
+
+type PendingTransfer interface {
+	Add(ctx context.Context, pt entity.PendingTransfer) (*entity.PendingTransfer, error)
+	GetForUpdate(ctx context.Context, id int) (*entity.PendingTransfer, error)
+	ListPending(ctx context.Context) ([]entity.PendingTransfer, error)
+	Resolve(ctx context.Context, id int, status, resolvedBy string, resolvedAt time.Time) error
+	PendingSince(ctx context.Context, username string, since time.Time) (int, error)
+}
+
+var pendingTransferColumns = []string{
+	"id", "fromUser", "toUser", "amount", "message", "status", "createdAt", "resolvedAt", "COALESCE(resolvedBy, '')",
+}
+
+func (r *PendingTransferRepo) Add(ctx context.Context, pt entity.PendingTransfer) (*entity.PendingTransfer, error) {
+	const op = "repository.pendingTransfer.Add"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Insert("pendingTransfer").
+		Columns("fromUser", "toUser", "amount", "message", "status").
+		Values(pt.FromUser, pt.ToUser, pt.Amount, pt.Message, pt.Status).
+		Suffix("RETURNING id, createdAt").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&pt.ID, &pt.CreatedAt); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &pt, nil
+}
+
+// GetForUpdate locks the row so that concurrent approve and reject calls are
+// serialized.
+func (r *PendingTransferRepo) GetForUpdate(ctx context.Context, id int) (*entity.PendingTransfer, error) {
+	const op = "repository.pendingTransfer.GetForUpdate"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select(pendingTransferColumns...).
+		From("pendingTransfer").
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	pt, err := scanPendingTransfer(conn.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return pt, nil
+}
+
+// ListPending returns transfers awaiting a decision, oldest first.
+func (r *PendingTransferRepo) ListPending(ctx context.Context) ([]entity.PendingTransfer, error) {
+	const op = "repository.pendingTransfer.ListPending"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select(pendingTransferColumns...).
+		From("pendingTransfer").
+		Where(sq.Eq{"status": entity.PendingTransferPending}).
+		OrderBy("id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var transfers []entity.PendingTransfer
+
+	for rows.Next() {
+		pt, err := scanPendingTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		transfers = append(transfers, *pt)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return transfers, nil
+}
+
+func (r *PendingTransferRepo) Resolve(ctx context.Context, id int, status, resolvedBy string, resolvedAt time.Time) error {
+	const op = "repository.pendingTransfer.Resolve"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Update("pendingTransfer").
+		Set("status", status).
+		Set("resolvedBy", resolvedBy).
+		Set("resolvedAt", resolvedAt).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	if _, err = conn.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// PendingSince sums the user's transfers still awaiting approval that were
+// created after since.
+func (r *PendingTransferRepo) PendingSince(ctx context.Context, username string, since time.Time) (int, error) {
+	const op = "repository.pendingTransfer.PendingSince"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("COALESCE(SUM(amount), 0)").
+		From("pendingTransfer").
+		Where(sq.Eq{"fromUser": username, "status": entity.PendingTransferPending}).
+		Where(sq.GtOrEq{"createdAt": since}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	var amount int
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&amount); err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return amount, nil
+}
+
+func scanPendingTransfer(row pgx.Row) (*entity.PendingTransfer, error) {
+	var pt entity.PendingTransfer
+
+	err := row.Scan(
+		&pt.ID, &pt.FromUser, &pt.ToUser, &pt.Amount, &pt.Message,
+		&pt.Status, &pt.CreatedAt, &pt.ResolvedAt, &pt.ResolvedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pt, nil
+}
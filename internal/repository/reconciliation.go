@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+
+	"avito-shop/internal/entity"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
+)
+
+type ReconciliationRepo struct {
+	*postgres.Postgres
+}
+
+func NewReconciliationRepo(pg *postgres.Postgres) *ReconciliationRepo {
+	return &ReconciliationRepo{pg}
+}
+
+//go:generate mockery --name=Reconciliation
+
+type Reconciliation interface {
+	CheckBalances(ctx context.Context) ([]entity.BalanceCheck, error)
+	SaveReport(ctx context.Context, report entity.DriftReport) (int, error)
+}
+
+// CheckBalances derives every user's balance from grants, received and sent
+// transfers, purchases and expired coins. Purchases are taken at the price
+// paid, as posted to the ledger, plus the baseline of purchases made before
+// the ledger existed. Both sides are read by a single statement so they come
+// from the same snapshot.
+func (r *ReconciliationRepo) CheckBalances(ctx context.Context) ([]entity.BalanceCheck, error) {
+	const op = "repository.reconciliation.CheckBalances"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("b.username", "b.coins").
+		Column(sq.Expr(`COALESCE((SELECT SUM(g.amount) FROM coinGrant g WHERE g.username = b.username), 0)
+			+ COALESCE((SELECT SUM(t.amount) FROM coinTransaction t WHERE t.toUser = b.username), 0)
+			- COALESCE((SELECT SUM(t.amount) FROM coinTransaction t WHERE t.fromUser = b.username), 0)
+			- COALESCE((SELECT -SUM(p.amount) FROM ledgerPosting p JOIN ledgerEntry e ON e.id = p.entryID
+				WHERE e.kind = ? AND p.account = ?::TEXT || b.username), 0)
+			- COALESCE((SELECT l.spent FROM legacyPurchase l WHERE l.username = b.username), 0)
+			- COALESCE((SELECT SUM(x.amount) FROM coinExpiry x WHERE x.username = b.username), 0)`,
+			entity.LedgerKindPurchase, entity.UserAccount(""))).
+		From("balance b").
+		OrderBy("b.username").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var checks []entity.BalanceCheck
+
+	for rows.Next() {
+		var c entity.BalanceCheck
+
+		if err = rows.Scan(&c.Username, &c.Actual, &c.Expected); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		checks = append(checks, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checks, nil
+}
+
+// SaveReport stores the run summary and its drifted users and returns the run ID.
+func (r *ReconciliationRepo) SaveReport(ctx context.Context, report entity.DriftReport) (int, error) {
+	const op = "repository.reconciliation.SaveReport"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Insert("reconciliationRun").
+		Columns("checkedAt", "users", "drifted", "totalDrift").
+		Values(report.CheckedAt, report.Users, len(report.Drifts), report.TotalDrift).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	var id int
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	if len(report.Drifts) == 0 {
+		return id, nil
+	}
+
+	insert := sq.Insert("balanceDrift").Columns("runID", "username", "expected", "actual")
+	for _, d := range report.Drifts {
+		insert = insert.Values(id, d.Username, d.Expected, d.Actual)
+	}
+
+	query, args, err = insert.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err = conn.Exec(ctx, query, args...); err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
+
+	"avito-shop/internal/entity"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
+)
+
+type ScheduleRepo struct {
+	*postgres.Postgres
+}
+
+func NewScheduleRepo(pg *postgres.Postgres) *ScheduleRepo {
+	return &ScheduleRepo{pg}
+}
+
+//go:generate mockery --name=Schedule
+
+type Schedule interface {
+	Add(ctx context.Context, st entity.ScheduledTransfer) (int, error)
+	Get(ctx context.Context, id int) (*entity.ScheduledTransfer, error)
+	ListByUser(ctx context.Context, username string) ([]entity.ScheduledTransfer, error)
+	Update(ctx context.Context, st entity.ScheduledTransfer) error
+	Delete(ctx context.Context, id int) error
+	ClaimDue(ctx context.Context, now time.Time) (*entity.ScheduledTransfer, error)
+}
+
+var scheduleColumns = []string{
+	"id", "fromUser", "toUser", "amount", "message", "repeat",
+	"nextRunAt", "startsAt", "active", "lastRunAt", "lastError", "failures",
+}
+
+func (r *ScheduleRepo) Add(ctx context.Context, st entity.ScheduledTransfer) (int, error) {
+	const op = "repository.schedule.Add"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Insert("scheduledTransfer").
+		Columns("fromUser", "toUser", "amount", "message", "repeat", "nextRunAt", "startsAt", "active").
+		Values(st.FromUser, st.ToUser, st.Amount, st.Message, st.Repeat, st.NextRunAt, st.StartsAt, st.Active).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	var id int
+
+	err = conn.QueryRow(ctx, query, args...).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *ScheduleRepo) Get(ctx context.Context, id int) (*entity.ScheduledTransfer, error) {
+	const op = "repository.schedule.Get"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select(scheduleColumns...).
+		From("scheduledTransfer").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	return r.queryOne(ctx, op, query, args...)
+}
+
+func (r *ScheduleRepo) ListByUser(ctx context.Context, username string) ([]entity.ScheduledTransfer, error) {
+	const op = "repository.schedule.ListByUser"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select(scheduleColumns...).
+		From("scheduledTransfer").
+		Where(sq.Eq{"fromUser": username}).
+		OrderBy("id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var transfers []entity.ScheduledTransfer
+
+	for rows.Next() {
+		st, err := scanScheduledTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		transfers = append(transfers, *st)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return transfers, nil
+}
+
+func (r *ScheduleRepo) Update(ctx context.Context, st entity.ScheduledTransfer) error {
+	const op = "repository.schedule.Update"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Update("scheduledTransfer").
+		SetMap(map[string]any{
+			"toUser":    st.ToUser,
+			"amount":    st.Amount,
+			"message":   st.Message,
+			"repeat":    st.Repeat,
+			"nextRunAt": st.NextRunAt,
+			"startsAt":  st.StartsAt,
+			"active":    st.Active,
+			"lastRunAt": st.LastRunAt,
+			"lastError": st.LastError,
+			"failures":  st.Failures,
+		}).
+		Where(sq.Eq{"id": st.ID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *ScheduleRepo) Delete(ctx context.Context, id int) error {
+	const op = "repository.schedule.Delete"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Delete("scheduledTransfer").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	}
+
+	return nil
+}
+
+// ClaimDue locks the earliest due transfer for the current transaction.
+// Rows locked by other instances are skipped, so each transfer is executed by
+// exactly one scheduler at a time.
+func (r *ScheduleRepo) ClaimDue(ctx context.Context, now time.Time) (*entity.ScheduledTransfer, error) {
+	const op = "repository.schedule.ClaimDue"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select(scheduleColumns...).
+		From("scheduledTransfer").
+		Where(sq.And{sq.Eq{"active": true}, sq.LtOrEq{"nextRunAt": now}}).
+		OrderBy("nextRunAt").
+		Limit(1).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	return r.queryOne(ctx, op, query, args...)
+}
+
+func (r *ScheduleRepo) queryOne(ctx context.Context, op, query string, args ...any) (*entity.ScheduledTransfer, error) {
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	st, err := scanScheduledTransfer(conn.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return st, nil
+}
+
+func scanScheduledTransfer(row pgx.Row) (*entity.ScheduledTransfer, error) {
+	var st entity.ScheduledTransfer
+
+	err := row.Scan(
+		&st.ID, &st.FromUser, &st.ToUser, &st.Amount, &st.Message, &st.Repeat,
+		&st.NextRunAt, &st.StartsAt, &st.Active, &st.LastRunAt, &st.LastError, &st.Failures,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &st, nil
+}
@@ -0,0 +1,358 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+
+	"avito-shop/internal/entity"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
+)
+
+type StatsRepo struct {
+	*postgres.Postgres
+}
+
+func NewStatsRepo(pg *postgres.Postgres) *StatsRepo {
+	return &StatsRepo{pg}
+}
+
+//go:generate mockery --name=Stats
+
+type Stats interface {
+	Economy(ctx context.Context, since time.Time) (*entity.EconomyStats, error)
+	BalanceDistribution(ctx context.Context, bounds []int) (*entity.BalanceDistribution, error)
+	LastRollupDay(ctx context.Context) (*time.Time, error)
+	FirstActivityDay(ctx context.Context) (*time.Time, error)
+	RollUpDay(ctx context.Context, day time.Time) error
+	ListDaily(ctx context.Context, r entity.StatsRange) ([]entity.DailyStats, error)
+	ListItemSales(ctx context.Context, r entity.StatsRange) ([]entity.ItemSales, error)
+}
+
+// Economy reads the current totals in a single statement: circulation from
+// balances, shop spending from inventories and transfers made since the given time.
+func (r *StatsRepo) Economy(ctx context.Context, since time.Time) (*entity.EconomyStats, error) {
+	const op = "repository.stats.Economy"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select().
+		Column("(SELECT COUNT(*) FROM balance)").
+		Column("(SELECT COALESCE(SUM(coins), 0) FROM balance)").
+		Column("(SELECT COALESCE(SUM(amount), 0) FROM coinGrant WHERE amount > 0)").
+		Column("(SELECT COALESCE(-SUM(amount), 0) FROM coinGrant WHERE amount < 0)").
+		Column("(SELECT COALESCE(SUM(amount), 0) FROM coinExpiry)").
+		Column(`(SELECT COALESCE(SUM(i.quantity * it.price), 0) FROM inventory i
+			JOIN item it ON it.name = i.item)`).
+		Column(sq.Expr(`(SELECT COALESCE(SUM(amount), 0) FROM coinTransaction
+			WHERE reversalOf IS NULL AND createdAt >= ?)`, since)).
+		Column(sq.Expr(`(SELECT COUNT(*) FROM coinTransaction
+			WHERE reversalOf IS NULL AND createdAt >= ?)`, since)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	var s entity.EconomyStats
+
+	err = conn.QueryRow(ctx, query, args...).Scan(
+		&s.Users, &s.Circulation, &s.Minted, &s.ClawedBack, &s.Expired,
+		&s.SpentInShop, &s.Transferred, &s.Transfers,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &s, nil
+}
+
+// BalanceDistribution summarises current balances. Bounds are the ascending
+// lower edges of the buckets; the first one should be 0.
+func (r *StatsRepo) BalanceDistribution(ctx context.Context, bounds []int) (*entity.BalanceDistribution, error) {
+	const op = "repository.stats.BalanceDistribution"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	builder := sq.Select(
+		"COUNT(*)",
+		"COALESCE(MIN(coins), 0)",
+		"COALESCE(MAX(coins), 0)",
+		"COALESCE(AVG(coins), 0)::float8",
+		"COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY coins), 0)",
+		"COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY coins), 0)",
+		"COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY coins), 0)",
+	).
+		From("balance")
+
+	buckets := make([]entity.BalanceBucket, len(bounds))
+
+	for i, from := range bounds {
+		buckets[i].From = from
+
+		if i+1 < len(bounds) {
+			to := bounds[i+1]
+			buckets[i].To = &to
+			builder = builder.Column(sq.Expr("COUNT(*) FILTER (WHERE coins >= ? AND coins < ?)", from, to))
+		} else {
+			builder = builder.Column(sq.Expr("COUNT(*) FILTER (WHERE coins >= ?)", from))
+		}
+	}
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	d := entity.BalanceDistribution{Buckets: buckets}
+
+	dest := []interface{}{&d.Users, &d.Min, &d.Max, &d.Mean, &d.Median, &d.P90, &d.P99}
+	for i := range buckets {
+		dest = append(dest, &buckets[i].Users)
+	}
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(dest...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &d, nil
+}
+
+// LastRollupDay returns the latest materialized day, or nil before the first rollup.
+func (r *StatsRepo) LastRollupDay(ctx context.Context) (*time.Time, error) {
+	const op = "repository.stats.LastRollupDay"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	day, err := r.day(ctx, sq.Select("MAX(day)").From("dailyCoinStats"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return day, nil
+}
+
+// FirstActivityDay returns the day of the oldest ledger entry, or nil if there is none.
+func (r *StatsRepo) FirstActivityDay(ctx context.Context) (*time.Time, error) {
+	const op = "repository.stats.FirstActivityDay"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	day, err := r.day(ctx, sq.Select("MIN(createdAt AT TIME ZONE 'UTC')::date").From("ledgerEntry"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return day, nil
+}
+
+func (r *StatsRepo) day(ctx context.Context, builder sq.SelectBuilder) (*time.Time, error) {
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	var day *time.Time
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&day); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return day, nil
+}
+
+// utcDate casts a timestamp argument to its UTC date; a plain ::date would
+// use the session time zone.
+const utcDate = "(?::timestamptz AT TIME ZONE 'UTC')::date"
+
+// RollUpDay materializes the totals and item sales of the UTC day starting at
+// day, replacing an earlier rollup of the same day.
+func (r *StatsRepo) RollUpDay(ctx context.Context, day time.Time) error {
+	const op = "repository.stats.RollUpDay"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	start, end := day, day.AddDate(0, 0, 1)
+
+	totals := sq.Select().
+		Column(sq.Expr(utcDate, start)).
+		Column(sq.Expr(`(SELECT COALESCE(SUM(amount), 0) FROM coinGrant
+			WHERE amount > 0 AND createdAt >= ? AND createdAt < ?)`, start, end)).
+		Column(sq.Expr(`(SELECT COALESCE(-SUM(amount), 0) FROM coinGrant
+			WHERE amount < 0 AND createdAt >= ? AND createdAt < ?)`, start, end)).
+		Column(sq.Expr(`(SELECT COALESCE(SUM(amount), 0) FROM coinExpiry
+			WHERE expiredAt >= ? AND expiredAt < ?)`, start, end)).
+		Column(sq.Expr(`(SELECT COALESCE(SUM(p.amount), 0) FROM ledgerPosting p
+			JOIN ledgerEntry e ON e.id = p.entryID
+			WHERE e.kind = ? AND p.account = ? AND e.createdAt >= ? AND e.createdAt < ?)`,
+			entity.LedgerKindPurchase, entity.ShopAccount, start, end)).
+		Column(sq.Expr(`(SELECT COALESCE(SUM(amount), 0) FROM coinTransaction
+			WHERE reversalOf IS NULL AND createdAt >= ? AND createdAt < ?)`, start, end)).
+		Column(sq.Expr(`(SELECT COUNT(*) FROM coinTransaction
+			WHERE reversalOf IS NULL AND createdAt >= ? AND createdAt < ?)`, start, end)).
+		Column(sq.Expr(`(SELECT COALESCE(SUM(p.amount), 0) FROM ledgerPosting p
+			JOIN ledgerEntry e ON e.id = p.entryID
+			WHERE p.account LIKE ? AND e.createdAt < ?)`, entity.UserAccount("%"), end))
+
+	query, args, err := sq.Insert("dailyCoinStats").
+		Columns("day", "minted", "clawedBack", "expired", "spentInShop", "transferred", "transfers", "circulation").
+		Select(totals).
+		Suffix(`ON CONFLICT (day) DO UPDATE SET
+			minted = EXCLUDED.minted, clawedBack = EXCLUDED.clawedBack, expired = EXCLUDED.expired,
+			spentInShop = EXCLUDED.spentInShop, transferred = EXCLUDED.transferred,
+			transfers = EXCLUDED.transfers, circulation = EXCLUDED.circulation, rolledUpAt = NOW()`).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	if _, err = conn.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	sales := sq.Select().
+		Column(sq.Expr(utcDate, start)).
+		Columns("e.reference", "COUNT(*)", "SUM(p.amount)").
+		From("ledgerEntry e").
+		Join("ledgerPosting p ON p.entryID = e.id").
+		Where(sq.Eq{"e.kind": entity.LedgerKindPurchase, "p.account": entity.ShopAccount}).
+		Where(sq.GtOrEq{"e.createdAt": start}).
+		Where(sq.Lt{"e.createdAt": end}).
+		GroupBy("e.reference")
+
+	query, args, err = sq.Insert("dailyItemSales").
+		Columns("day", "item", "sold", "coins").
+		Select(sales).
+		Suffix("ON CONFLICT (day, item) DO UPDATE SET sold = EXCLUDED.sold, coins = EXCLUDED.coins").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err = conn.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *StatsRepo) ListDaily(ctx context.Context, rng entity.StatsRange) ([]entity.DailyStats, error) {
+	const op = "repository.stats.ListDaily"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	builder := sq.Select("day", "minted", "clawedBack", "expired", "spentInShop", "transferred", "transfers", "circulation").
+		From("dailyCoinStats")
+
+	query, args, err := withDays(builder, rng).
+		OrderBy("day").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	days := []entity.DailyStats{}
+
+	for rows.Next() {
+		var d entity.DailyStats
+
+		err = rows.Scan(&d.Day, &d.Minted, &d.ClawedBack, &d.Expired,
+			&d.SpentInShop, &d.Transferred, &d.Transfers, &d.Circulation)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		days = append(days, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return days, nil
+}
+
+func (r *StatsRepo) ListItemSales(ctx context.Context, rng entity.StatsRange) ([]entity.ItemSales, error) {
+	const op = "repository.stats.ListItemSales"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	builder := sq.Select("day", "item", "sold", "coins").From("dailyItemSales")
+
+	query, args, err := withDays(builder, rng).
+		OrderBy("day", "sold DESC", "item").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	sales := []entity.ItemSales{}
+
+	for rows.Next() {
+		var s entity.ItemSales
+		if err = rows.Scan(&s.Day, &s.Item, &s.Sold, &s.Coins); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		sales = append(sales, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sales, nil
+}
+
+func withDays(builder sq.SelectBuilder, rng entity.StatsRange) sq.SelectBuilder {
+	if rng.From != nil {
+		builder = builder.Where(sq.GtOrEq{"day": *rng.From})
+	}
+
+	if rng.To != nil {
+		builder = builder.Where(sq.LtOrEq{"day": *rng.To})
+	}
+
+	return builder
+}
@@ -2,13 +2,18 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
 
 	"avito-shop/internal/entity"
+	e "avito-shop/pkg/errors"
 	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
 )
 
 type TransactionRepo struct {
@@ -19,20 +24,33 @@ type TransactionRepo struct {
 
 type Transaction interface {
 	AddTransaction(ctx context.Context, txn entity.CoinTransaction) error
-	GetReceivedTransactions(ctx context.Context, username string) ([]entity.ReceivedTransaction, error)
-	GetSentTransactions(ctx context.Context, username string) ([]entity.SentTransaction, error)
+	GetReceivedTransactions(ctx context.Context, username string, limit int) ([]entity.ReceivedTransaction, error)
+	GetSentTransactions(ctx context.Context, username string, limit int) ([]entity.SentTransaction, error)
+	ListTransactions(ctx context.Context, f entity.TransactionFilter) ([]entity.CoinTransaction, error)
+	GetReceivedSummary(ctx context.Context, username string) ([]entity.ReceivedSummary, error)
+	GetSentSummary(ctx context.Context, username string) ([]entity.SentSummary, error)
+	SentSince(ctx context.Context, username string, since time.Time) (int, error)
+	GetForUpdate(ctx context.Context, id int) (*entity.CoinTransaction, error)
+	AddReversal(ctx context.Context, txn entity.CoinTransaction, reversedBy string, reversedAt time.Time) (int, error)
 }
 
 func NewTransactionRepo(pg *postgres.Postgres) *TransactionRepo {
 	return &TransactionRepo{pg}
 }
 
+var coinTransactionColumns = []string{
+	"id", "fromUser", "toUser", "amount", "message", "reversalOf", "reversedAt", "createdAt",
+}
+
 func (r *TransactionRepo) AddTransaction(ctx context.Context, txn entity.CoinTransaction) error {
 	const op = "repository.transaction.AddTransaction"
 
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
 	query, args, err := sq.Insert("coinTransaction").
-		Columns("fromUser", "toUser", "amount").
-		Values(txn.FromUser, txn.ToUser, txn.Amount).
+		Columns("fromUser", "toUser", "amount", "message").
+		Values(txn.FromUser, txn.ToUser, txn.Amount, txn.Message).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
@@ -49,12 +67,21 @@ func (r *TransactionRepo) AddTransaction(ctx context.Context, txn entity.CoinTra
 	return nil
 }
 
-func (r *TransactionRepo) GetReceivedTransactions(ctx context.Context, username string) ([]entity.ReceivedTransaction, error) {
+// GetReceivedTransactions returns the latest limit transfers to the user, newest first.
+func (r *TransactionRepo) GetReceivedTransactions(ctx context.Context,
+	username string,
+	limit int,
+) ([]entity.ReceivedTransaction, error) {
 	const op = "repository.transaction.GetReceivedTransactions"
 
-	query, args, err := sq.Select("fromUser", "amount").
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("id", "fromUser", "amount", "message", "reversedAt IS NOT NULL", "createdAt").
 		From("coinTransaction").
 		Where(sq.Eq{"toUser": username}).
+		OrderBy("id DESC").
+		Limit(uint64(limit)).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
@@ -74,7 +101,7 @@ func (r *TransactionRepo) GetReceivedTransactions(ctx context.Context, username
 
 	for rows.Next() {
 		var item entity.ReceivedTransaction
-		if err = rows.Scan(&item.FromUser, &item.Amount); err != nil {
+		if err = rows.Scan(&item.ID, &item.FromUser, &item.Amount, &item.Message, &item.Reversed, &item.CreatedAt); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
@@ -88,12 +115,21 @@ func (r *TransactionRepo) GetReceivedTransactions(ctx context.Context, username
 	return receivedTxns, nil
 }
 
-func (r *TransactionRepo) GetSentTransactions(ctx context.Context, username string) ([]entity.SentTransaction, error) {
+// GetSentTransactions returns the latest limit transfers from the user, newest first.
+func (r *TransactionRepo) GetSentTransactions(ctx context.Context,
+	username string,
+	limit int,
+) ([]entity.SentTransaction, error) {
 	const op = "repository.transaction.GetSentTransactions"
 
-	query, args, err := sq.Select("toUser", "amount").
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("id", "toUser", "amount", "message", "reversedAt IS NOT NULL", "createdAt").
 		From("Cointransaction").
 		Where(sq.Eq{"fromUser": username}).
+		OrderBy("id DESC").
+		Limit(uint64(limit)).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
@@ -113,7 +149,7 @@ func (r *TransactionRepo) GetSentTransactions(ctx context.Context, username stri
 
 	for rows.Next() {
 		var item entity.SentTransaction
-		if err = rows.Scan(&item.ToUser, &item.Amount); err != nil {
+		if err = rows.Scan(&item.ID, &item.ToUser, &item.Amount, &item.Message, &item.Reversed, &item.CreatedAt); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
@@ -126,3 +162,297 @@ func (r *TransactionRepo) GetSentTransactions(ctx context.Context, username stri
 
 	return sentTxns, nil
 }
+
+// SentSince sums the coins the user transferred after since. Reversals are
+// made by admins and do not count.
+func (r *TransactionRepo) SentSince(ctx context.Context, username string, since time.Time) (int, error) {
+	const op = "repository.transaction.SentSince"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("COALESCE(SUM(amount), 0)").
+		From("coinTransaction").
+		Where(sq.Eq{"fromUser": username, "reversalOf": nil}).
+		Where(sq.GtOrEq{"createdAt": since}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	var amount int
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&amount); err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return amount, nil
+}
+
+// GetForUpdate locks the transaction row so that it is reversed at most once.
+func (r *TransactionRepo) GetForUpdate(ctx context.Context, id int) (*entity.CoinTransaction, error) {
+	const op = "repository.transaction.GetForUpdate"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select(coinTransactionColumns...).
+		From("coinTransaction").
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	txn, err := scanCoinTransaction(conn.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return txn, nil
+}
+
+// AddReversal stores the compensating transaction txn and marks the one it
+// reverses. It returns the ID of the compensating transaction.
+func (r *TransactionRepo) AddReversal(ctx context.Context,
+	txn entity.CoinTransaction,
+	reversedBy string,
+	reversedAt time.Time,
+) (int, error) {
+	const op = "repository.transaction.AddReversal"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Insert("coinTransaction").
+		Columns("fromUser", "toUser", "amount", "message", "reversalOf").
+		Values(txn.FromUser, txn.ToUser, txn.Amount, txn.Message, txn.ReversalOf).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	var id int
+
+	if err = conn.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	query, args, err = sq.Update("coinTransaction").
+		Set("reversedAt", reversedAt).
+		Set("reversedBy", reversedBy).
+		Where(sq.Eq{"id": txn.ReversalOf}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err = conn.Exec(ctx, query, args...); err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// ListTransactions returns a page of transactions involving the user, newest
+// first. Pages are keyed by ID, which grows with creation time.
+func (r *TransactionRepo) ListTransactions(ctx context.Context, f entity.TransactionFilter) ([]entity.CoinTransaction, error) {
+	const op = "repository.transaction.ListTransactions"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	builder := sq.Select(coinTransactionColumns...).
+		From("coinTransaction").
+		OrderBy("id DESC").
+		Limit(uint64(f.Limit))
+
+	switch f.Direction {
+	case entity.DirectionIncoming:
+		builder = builder.Where(sq.Eq{"toUser": f.Username})
+	case entity.DirectionOutgoing:
+		builder = builder.Where(sq.Eq{"fromUser": f.Username})
+	default:
+		builder = builder.Where(sq.Or{sq.Eq{"fromUser": f.Username}, sq.Eq{"toUser": f.Username}})
+	}
+
+	if f.Counterparty != "" {
+		builder = builder.Where(sq.Or{
+			sq.Eq{"fromUser": f.Username, "toUser": f.Counterparty},
+			sq.Eq{"fromUser": f.Counterparty, "toUser": f.Username},
+		})
+	}
+
+	if f.From != nil {
+		builder = builder.Where(sq.GtOrEq{"createdAt": *f.From})
+	}
+
+	if f.To != nil {
+		builder = builder.Where(sq.Lt{"createdAt": *f.To})
+	}
+
+	if f.Before > 0 {
+		builder = builder.Where(sq.Lt{"id": f.Before})
+	}
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var txns []entity.CoinTransaction
+
+	for rows.Next() {
+		txn, err := scanCoinTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		txns = append(txns, *txn)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return txns, nil
+}
+
+func scanCoinTransaction(row pgx.Row) (*entity.CoinTransaction, error) {
+	var txn entity.CoinTransaction
+
+	err := row.Scan(
+		&txn.ID, &txn.FromUser, &txn.ToUser, &txn.Amount, &txn.Message,
+		&txn.ReversalOf, &txn.ReversedAt, &txn.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &txn, nil
+}
+
+// netTransferAmount is what is left of transfer t after its reversal r, if
+// any. A reversal may take back only part of the coins, so the rest counts.
+const netTransferAmount = "t.amount - COALESCE(r.amount, 0)"
+
+// GetReceivedSummary groups transfers to the user by sender, largest total
+// first. Reversals are left out and reversed transfers count only the coins
+// that were not taken back.
+func (r *TransactionRepo) GetReceivedSummary(ctx context.Context, username string) ([]entity.ReceivedSummary, error) {
+	const op = "repository.transaction.GetReceivedSummary"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("t.fromUser", "SUM("+netTransferAmount+")", "COUNT(*)").
+		From("coinTransaction t").
+		LeftJoin("coinTransaction r ON r.reversalOf = t.id").
+		Where(sq.Eq{"t.toUser": username, "t.reversalOf": nil}).
+		Where(netTransferAmount+" > 0").
+		GroupBy("t.fromUser").
+		OrderBy("SUM("+netTransferAmount+") DESC", "t.fromUser").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	summary := []entity.ReceivedSummary{}
+
+	for rows.Next() {
+		var item entity.ReceivedSummary
+		if err = rows.Scan(&item.FromUser, &item.Amount, &item.Count); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		summary = append(summary, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return summary, nil
+}
+
+// GetSentSummary groups transfers from the user by recipient, largest total
+// first. Reversals are left out and reversed transfers count only the coins
+// that were not taken back.
+func (r *TransactionRepo) GetSentSummary(ctx context.Context, username string) ([]entity.SentSummary, error) {
+	const op = "repository.transaction.GetSentSummary"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("t.toUser", "SUM("+netTransferAmount+")", "COUNT(*)").
+		From("coinTransaction t").
+		LeftJoin("coinTransaction r ON r.reversalOf = t.id").
+		Where(sq.Eq{"t.fromUser": username, "t.reversalOf": nil}).
+		Where(netTransferAmount+" > 0").
+		GroupBy("t.toUser").
+		OrderBy("SUM("+netTransferAmount+") DESC", "t.toUser").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	summary := []entity.SentSummary{}
+
+	for rows.Next() {
+		var item entity.SentSummary
+		if err = rows.Scan(&item.ToUser, &item.Amount, &item.Count); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		summary = append(summary, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return summary, nil
+}
@@ -13,6 +13,7 @@ import (
 	"avito-shop/internal/entity"
 	e "avito-shop/pkg/errors"
 	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
 )
 
 type UserRepo struct {
@@ -28,12 +29,16 @@ func NewUserRepo(pg *postgres.Postgres) *UserRepo {
 type User interface {
 	Get(ctx context.Context, username string) (*entity.User, error)
 	Add(ctx context.Context, user entity.User) error
+	SetAdmin(ctx context.Context, username string, admin bool) error
 }
 
 func (r *UserRepo) Get(ctx context.Context, username string) (*entity.User, error) {
 	const op = "repository.user.Get"
 
-	query, args, err := sq.Select("username", "password").
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Select("username", "password", "isAdmin").
 		From("users").
 		Where(sq.Eq{"username": username}).
 		PlaceholderFormat(sq.Dollar).
@@ -57,7 +62,7 @@ func (r *UserRepo) Get(ctx context.Context, username string) (*entity.User, erro
 		return nil, fmt.Errorf("%s: %w", op, e.ErrNotFound)
 	}
 
-	err = rows.Scan(&user.Username, &user.Password)
+	err = rows.Scan(&user.Username, &user.Password, &user.IsAdmin)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("%s:%w", op, e.ErrNotFound)
@@ -76,9 +81,12 @@ func (r *UserRepo) Get(ctx context.Context, username string) (*entity.User, erro
 func (r *UserRepo) Add(ctx context.Context, user entity.User) error {
 	const op = "repository.user.Add"
 
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
 	query, args, err := sq.Insert("users").
-		Columns("username", "password").
-		Values(user.Username, user.Password).
+		Columns("username", "password", "isAdmin").
+		Values(user.Username, user.Password, user.IsAdmin).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
@@ -94,3 +102,33 @@ func (r *UserRepo) Add(ctx context.Context, user entity.User) error {
 
 	return nil
 }
+
+// SetAdmin grants or revokes the admin role of an existing user.
+func (r *UserRepo) SetAdmin(ctx context.Context, username string, admin bool) error {
+	const op = "repository.user.SetAdmin"
+
+	ctx, span := tracing.StartQuery(ctx, op)
+	defer span.End()
+
+	query, args, err := sq.Update("users").
+		Set("isAdmin", admin).
+		Where(sq.Eq{"username": username}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, r.Pool)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	}
+
+	return nil
+}
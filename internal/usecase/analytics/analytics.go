@@ -0,0 +1,176 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/repository"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
+)
+
+// UseCase serves leaderboards from an in-memory cache. Refresh rebuilds every
+// window; a window missing from the cache is computed on first read.
+type UseCase struct {
+	repoLeaderboard LeaderboardRepo
+	size            int
+
+	mu         sync.RWMutex
+	cache      map[string]*entity.Leaderboard
+	generation int
+}
+
+func New(rl *repository.LeaderboardRepo, size int) *UseCase {
+	return &UseCase{
+		repoLeaderboard: rl,
+		size:            size,
+		cache:           make(map[string]*entity.Leaderboard),
+	}
+}
+
+//go:generate mockery --name=Analytics
+
+type (
+	Analytics interface {
+		Leaderboard(ctx context.Context, window string) (*entity.Leaderboard, error)
+		Refresh(ctx context.Context) error
+		SetOptOut(ctx context.Context, username string, optOut bool) error
+	}
+
+	LeaderboardRepo interface {
+		TopReceivers(ctx context.Context, since *time.Time, limit int) ([]entity.UserScore, error)
+		TopSenders(ctx context.Context, since *time.Time, limit int) ([]entity.UserScore, error)
+		MostGenerous(ctx context.Context, since *time.Time, limit int) ([]entity.UserScore, error)
+		MostBoughtItems(ctx context.Context, since *time.Time, limit int) ([]entity.ItemScore, error)
+		SetOptOut(ctx context.Context, username string, optOut bool) error
+	}
+)
+
+func (uc *UseCase) Leaderboard(ctx context.Context, window string) (*entity.Leaderboard, error) {
+	const op = "usecase.analytics.Leaderboard"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if _, ok := entity.LeaderboardSince(window, time.Now()); !ok {
+		return nil, fmt.Errorf("%s: %w", op, e.ErrInvalidRequest)
+	}
+
+	uc.mu.RLock()
+	board, ok := uc.cache[window]
+	generation := uc.generation
+	uc.mu.RUnlock()
+
+	if ok {
+		return board, nil
+	}
+
+	board, err := uc.compute(ctx, window, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.store(generation, map[string]*entity.Leaderboard{window: board})
+
+	return board, nil
+}
+
+// Refresh recomputes the leaderboards of every window.
+func (uc *UseCase) Refresh(ctx context.Context) error {
+	const op = "usecase.analytics.Refresh"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	uc.mu.RLock()
+	generation := uc.generation
+	uc.mu.RUnlock()
+
+	now := time.Now()
+	boards := make(map[string]*entity.Leaderboard, len(entity.LeaderboardWindows))
+
+	for _, window := range entity.LeaderboardWindows {
+		board, err := uc.compute(ctx, window, now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		boards[window] = board
+	}
+
+	uc.store(generation, boards)
+
+	return nil
+}
+
+// SetOptOut changes the privacy flag and drops the cache, so the user leaves
+// or rejoins the leaderboards on the next read rather than the next refresh.
+func (uc *UseCase) SetOptOut(ctx context.Context, username string, optOut bool) error {
+	const op = "usecase.analytics.SetOptOut"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := uc.repoLeaderboard.SetOptOut(ctx, username, optOut); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	uc.mu.Lock()
+	uc.cache = make(map[string]*entity.Leaderboard)
+	uc.generation++
+	uc.mu.Unlock()
+
+	return nil
+}
+
+// store caches boards unless the cache was dropped while they were computed,
+// in which case they may still list a user who has just opted out.
+func (uc *UseCase) store(generation int, boards map[string]*entity.Leaderboard) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if generation != uc.generation {
+		return
+	}
+
+	for window, board := range boards {
+		uc.cache[window] = board
+	}
+}
+
+func (uc *UseCase) compute(ctx context.Context, window string, now time.Time) (*entity.Leaderboard, error) {
+	since, _ := entity.LeaderboardSince(window, now)
+
+	board := &entity.Leaderboard{
+		Window:      window,
+		Since:       since,
+		RefreshedAt: now,
+	}
+
+	var err error
+
+	board.TopReceivers, err = uc.repoLeaderboard.TopReceivers(ctx, since, uc.size)
+	if err != nil {
+		return nil, err
+	}
+
+	board.TopSenders, err = uc.repoLeaderboard.TopSenders(ctx, since, uc.size)
+	if err != nil {
+		return nil, err
+	}
+
+	board.MostGenerous, err = uc.repoLeaderboard.MostGenerous(ctx, since, uc.size)
+	if err != nil {
+		return nil, err
+	}
+
+	board.MostBought, err = uc.repoLeaderboard.MostBoughtItems(ctx, since, uc.size)
+	if err != nil {
+		return nil, err
+	}
+
+	return board, nil
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Analytics is an autogenerated mock type for the Analytics type
+type Analytics struct {
+	mock.Mock
+}
+
+// Leaderboard provides a mock function with given fields: ctx, window
+func (_m *Analytics) Leaderboard(ctx context.Context, window string) (*entity.Leaderboard, error) {
+	ret := _m.Called(ctx, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Leaderboard")
+	}
+
+	var r0 *entity.Leaderboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Leaderboard, error)); ok {
+		return rf(ctx, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Leaderboard); ok {
+		r0 = rf(ctx, window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Leaderboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Refresh provides a mock function with given fields: ctx
+func (_m *Analytics) Refresh(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetOptOut provides a mock function with given fields: ctx, username, optOut
+func (_m *Analytics) SetOptOut(ctx context.Context, username string, optOut bool) error {
+	ret := _m.Called(ctx, username, optOut)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetOptOut")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, username, optOut)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAnalytics creates a new instance of Analytics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAnalytics(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Analytics {
+	mock := &Analytics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -5,29 +5,44 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"golang.org/x/exp/slog"
 
 	"avito-shop/internal/entity"
 	"avito-shop/internal/repository"
 	e "avito-shop/pkg/errors"
 	"avito-shop/pkg/jwt"
-)
-
-const (
-	newUserBalance = 1000
+	"avito-shop/pkg/logger"
+	"avito-shop/pkg/metrics"
+	"avito-shop/pkg/tracing"
 )
 
 type UseCase struct {
-	repoUser    UserRepo
-	repoBalance BalanceRepo
-	trManager   *manager.Manager
+	repoUser        UserRepo
+	repoBalance     BalanceRepo
+	repoGrant       GrantRepo
+	repoLedger      LedgerRepo
+	repoLot         LotRepo
+	trManager       trm.Manager
+	onboardingGrant int
 }
 
-func New(ru *repository.UserRepo, rb *repository.BalanceRepo, trManager *manager.Manager) *UseCase {
+func New(ru *repository.UserRepo,
+	rb *repository.BalanceRepo,
+	rg *repository.GrantRepo,
+	rl *repository.LedgerRepo,
+	rlot *repository.LotRepo,
+	trManager trm.Manager,
+	onboardingGrant int,
+) *UseCase {
 	return &UseCase{
-		repoUser:    ru,
-		repoBalance: rb,
-		trManager:   trManager,
+		repoUser:        ru,
+		repoBalance:     rb,
+		repoGrant:       rg,
+		repoLedger:      rl,
+		repoLot:         rlot,
+		trManager:       trManager,
+		onboardingGrant: onboardingGrant,
 	}
 }
 
@@ -41,16 +56,32 @@ type (
 	UserRepo interface {
 		Get(context.Context, string) (*entity.User, error)
 		Add(context.Context, entity.User) error
+		SetAdmin(ctx context.Context, username string, admin bool) error
 	}
 
 	BalanceRepo interface {
 		InitBalance(ctx context.Context, username string, amount int) error
 	}
+
+	GrantRepo interface {
+		Add(ctx context.Context, g entity.CoinGrant) (*entity.CoinGrant, error)
+	}
+
+	LedgerRepo interface {
+		Record(ctx context.Context, entry entity.LedgerEntry) error
+	}
+
+	LotRepo interface {
+		AddLot(ctx context.Context, username, source string, amount int) error
+	}
 )
 
 func (uc *UseCase) Login(ctx context.Context, in entity.User) (string, error) {
 	const op = "usecase.auth.Login"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	var token string
 
 	var err error
@@ -60,6 +91,9 @@ func (uc *UseCase) Login(ctx context.Context, in entity.User) (string, error) {
 	err = uc.trManager.Do(ctx, func(ctx context.Context) error {
 		user, err = uc.repoUser.Get(ctx, in.Username)
 		if errors.Is(err, e.ErrNotFound) {
+			// Signing up never grants the admin role, see CreateAdmin.
+			in.IsAdmin = false
+
 			token, err = uc.register(ctx, in)
 			if err != nil {
 				return fmt.Errorf("%s: failed to register user: %w", op, err)
@@ -77,36 +111,108 @@ func (uc *UseCase) Login(ctx context.Context, in entity.User) (string, error) {
 	}
 
 	if token != "" {
+		metrics.Login(true)
+		logger.FromContext(ctx, slog.Default()).Info("user registered", slog.String("user", in.Username))
+
 		return token, nil
 	}
 
 	if user.Password != in.Password {
+		metrics.Login(false)
+		logger.FromContext(ctx, slog.Default()).Warn("login failed: wrong password", slog.String("user", in.Username))
+
 		return "", fmt.Errorf("%s: %w", op, e.ErrInvalidCredentials)
 	}
 
-	token, err = jwt.GenerateToken(in.Username)
+	token, err = jwt.GenerateToken(user.Username, user.IsAdmin)
 	if err != nil {
 		return "", fmt.Errorf("%s: failed to generate token: %w", op, err)
 	}
 
+	metrics.Login(true)
+
 	return token, nil
 }
 
+// CreateAdmin provisions an admin account, or grants the admin role to an
+// existing user, who keeps their password. It is the only way to become an
+// admin.
+func (uc *UseCase) CreateAdmin(ctx context.Context, in entity.User) error {
+	const op = "usecase.auth.CreateAdmin"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		_, err := uc.repoUser.Get(ctx, in.Username)
+		if errors.Is(err, e.ErrNotFound) {
+			in.IsAdmin = true
+
+			return uc.provision(ctx, in)
+		} else if err != nil {
+			return err
+		}
+
+		return uc.repoUser.SetAdmin(ctx, in.Username, true)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	logger.FromContext(ctx, slog.Default()).Info("admin provisioned", slog.String("user", in.Username))
+
+	return nil
+}
+
 func (uc *UseCase) register(ctx context.Context, in entity.User) (string, error) {
 	const op = "usecase.auth.Register"
 
-	if err := uc.repoUser.Add(ctx, in); err != nil {
-		return "", fmt.Errorf("%s:%w", op, err)
-	}
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	if err := uc.repoBalance.InitBalance(ctx, in.Username, newUserBalance); err != nil {
+	if err := uc.provision(ctx, in); err != nil {
 		return "", fmt.Errorf("%s:%w", op, err)
 	}
 
-	token, err := jwt.GenerateToken(in.Username)
+	token, err := jwt.GenerateToken(in.Username, in.IsAdmin)
 	if err != nil {
 		return "", fmt.Errorf("%s:%w", op, err)
 	}
 
 	return token, nil
 }
+
+// provision creates the user with a balance holding the onboarding grant.
+func (uc *UseCase) provision(ctx context.Context, in entity.User) error {
+	const op = "usecase.auth.provision"
+
+	if err := uc.repoUser.Add(ctx, in); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	if err := uc.repoBalance.InitBalance(ctx, in.Username, uc.onboardingGrant); err != nil {
+		return fmt.Errorf("%s:%w", op, err)
+	}
+
+	if uc.onboardingGrant > 0 {
+		_, err := uc.repoGrant.Add(ctx, entity.CoinGrant{
+			Username: in.Username,
+			Amount:   uc.onboardingGrant,
+			Reason:   entity.GrantReasonOnboarding,
+			Author:   entity.GrantAuthorSystem,
+		})
+		if err != nil {
+			return fmt.Errorf("%s:%w", op, err)
+		}
+
+		if err = uc.repoLedger.Record(ctx, entity.GrantEntry(in.Username, uc.onboardingGrant)); err != nil {
+			return fmt.Errorf("%s:%w", op, err)
+		}
+
+		if err = uc.repoLot.AddLot(ctx, in.Username, entity.LotSourceGrant, uc.onboardingGrant); err != nil {
+			return fmt.Errorf("%s:%w", op, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"avito-shop/internal/entity"
+	repomocks "avito-shop/internal/repository/mocks"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/jwt"
+)
+
+func TestMain(m *testing.M) {
+	if err := jwt.SetKey("0123456789abcdef0123456789abcdef"); err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+// txManager runs the closures without a database.
+type txManager struct{}
+
+func (txManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (txManager) DoWithSettings(ctx context.Context, _ trm.Settings, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestUseCase_Login_SignUpIsNotAdmin(t *testing.T) {
+	users := new(repomocks.User)
+	balance := new(repomocks.Balance)
+
+	users.On("Get", mock.Anything, "root").Return(nil, e.ErrNotFound)
+	users.On("Add", mock.Anything, entity.User{Username: "root", Password: "secret"}).Return(nil)
+	balance.On("InitBalance", mock.Anything, "root", 0).Return(nil)
+
+	uc := &UseCase{repoUser: users, repoBalance: balance, trManager: txManager{}}
+
+	token, err := uc.Login(context.Background(), entity.User{Username: "root", Password: "secret", IsAdmin: true})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+
+	users.AssertExpectations(t)
+	balance.AssertExpectations(t)
+}
+
+func TestUseCase_CreateAdmin_NewUser(t *testing.T) {
+	users := new(repomocks.User)
+	balance := new(repomocks.Balance)
+
+	users.On("Get", mock.Anything, "root").Return(nil, e.ErrNotFound)
+	users.On("Add", mock.Anything, entity.User{Username: "root", Password: "secret", IsAdmin: true}).Return(nil)
+	balance.On("InitBalance", mock.Anything, "root", 0).Return(nil)
+
+	uc := &UseCase{repoUser: users, repoBalance: balance, trManager: txManager{}}
+
+	err := uc.CreateAdmin(context.Background(), entity.User{Username: "root", Password: "secret"})
+
+	assert.NoError(t, err)
+
+	users.AssertExpectations(t)
+	balance.AssertExpectations(t)
+}
+
+func TestUseCase_CreateAdmin_ExistingUser(t *testing.T) {
+	users := new(repomocks.User)
+
+	users.On("Get", mock.Anything, "root").Return(&entity.User{Username: "root", Password: "old"}, nil)
+	users.On("SetAdmin", mock.Anything, "root", true).Return(nil)
+
+	uc := &UseCase{repoUser: users, trManager: txManager{}}
+
+	err := uc.CreateAdmin(context.Background(), entity.User{Username: "root", Password: "secret"})
+
+	assert.NoError(t, err)
+
+	users.AssertExpectations(t)
+	users.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+}
@@ -4,26 +4,37 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"golang.org/x/exp/slog"
 
 	"avito-shop/internal/entity"
 	"avito-shop/internal/repository"
 	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/logger"
+	"avito-shop/pkg/metrics"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
 )
 
 type UseCase struct {
 	repoBalance   BalanceRepo
 	repoInventory InventoryRepo
-	trManager     *manager.Manager
+	repoLedger    LedgerRepo
+	repoLot       LotRepo
+	trManager     trm.Manager
 }
 
 func New(rB *repository.BalanceRepo,
 	rI *repository.InventoryRepo,
-	trManager *manager.Manager,
+	rL *repository.LedgerRepo,
+	rLot *repository.LotRepo,
+	trManager trm.Manager,
 ) *UseCase {
 	return &UseCase{
 		repoBalance:   rB,
 		repoInventory: rI,
+		repoLedger:    rL,
+		repoLot:       rLot,
 		trManager:     trManager,
 	}
 }
@@ -36,8 +47,8 @@ type (
 	}
 
 	BalanceRepo interface {
-		GetUserBalance(ctx context.Context, username string) (int, error)
 		DecreaseBalance(ctx context.Context, username string, amount int) error
+		LockBalances(ctx context.Context, usernames ...string) (map[string]int, error)
 	}
 
 	InventoryRepo interface {
@@ -46,30 +57,45 @@ type (
 		ExistsInventoryItem(ctx context.Context, username, item string) (bool, error)
 		IncrementInventoryItemQuantity(ctx context.Context, username, item string) error
 	}
+
+	LedgerRepo interface {
+		Record(ctx context.Context, entry entity.LedgerEntry) error
+	}
+
+	LotRepo interface {
+		ConsumeLots(ctx context.Context, username string, amount int) error
+	}
 )
 
 func (uc *UseCase) BuyItem(ctx context.Context, username, item string) error {
 	const op = "usecase.BuyItem"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	price, err := uc.repoInventory.GetItemPrice(ctx, item)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	err = uc.trManager.Do(ctx, func(ctx context.Context) error {
-		balance, err := uc.repoBalance.GetUserBalance(ctx, username)
+		balances, err := uc.repoBalance.LockBalances(ctx, username)
 		if err != nil {
-			return err
+			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		if balance < price {
-			return fmt.Errorf("%s: %w", op, e.ErrInvalidCredentials)
+		if balances[username] < price {
+			return fmt.Errorf("%s: %w", op, e.ErrInsufficientFunds)
 		}
 
 		if err = uc.repoBalance.DecreaseBalance(ctx, username, price); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
+		if err = uc.repoLot.ConsumeLots(ctx, username, price); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
 		exists, err := uc.repoInventory.ExistsInventoryItem(ctx, username, item)
 		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
@@ -90,11 +116,20 @@ func (uc *UseCase) BuyItem(ctx context.Context, username, item string) error {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
+		if err = uc.repoLedger.Record(ctx, entity.PurchaseEntry(username, item, price)); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
 		return nil
 	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	// Callers may nest the purchase in their own transaction, so count the
+	// item only once that commits.
+	postgres.AfterCommit(ctx, func() { metrics.ItemBought(item) })
+	logger.FromContext(ctx, slog.Default()).Info("item purchased", slog.String("user", username), slog.String("item", item))
+
 	return nil
 }
@@ -0,0 +1,214 @@
+package coinrequest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/repository"
+	"avito-shop/internal/usecase/send"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
+)
+
+type UseCase struct {
+	repoCoinRequest CoinRequestRepo
+	repoBalance     BalanceRepo
+	sendUC          send.Send
+	trManager       trm.Manager
+	ttl             time.Duration
+}
+
+func New(rc *repository.CoinRequestRepo,
+	rb *repository.BalanceRepo,
+	sendUC send.Send,
+	trManager trm.Manager,
+	ttl time.Duration,
+) *UseCase {
+	return &UseCase{
+		repoCoinRequest: rc,
+		repoBalance:     rb,
+		sendUC:          sendUC,
+		trManager:       trManager,
+		ttl:             ttl,
+	}
+}
+
+//go:generate mockery --name=CoinRequest
+
+type (
+	CoinRequest interface {
+		Create(ctx context.Context, req entity.CoinRequest) (*entity.CoinRequest, error)
+		ListIncoming(ctx context.Context, username string) ([]entity.CoinRequest, error)
+		ListOutgoing(ctx context.Context, username string) ([]entity.CoinRequest, error)
+		Accept(ctx context.Context, username string, id int) (*entity.CoinRequest, error)
+		Decline(ctx context.Context, username string, id int) (*entity.CoinRequest, error)
+	}
+
+	CoinRequestRepo interface {
+		Add(ctx context.Context, req entity.CoinRequest) (*entity.CoinRequest, error)
+		GetForUpdate(ctx context.Context, id int) (*entity.CoinRequest, error)
+		ListByPayer(ctx context.Context, username string) ([]entity.CoinRequest, error)
+		ListByRequester(ctx context.Context, username string) ([]entity.CoinRequest, error)
+		Resolve(ctx context.Context, id int, status string, resolvedAt time.Time) error
+	}
+
+	BalanceRepo interface {
+		GetUserBalance(ctx context.Context, username string) (int, error)
+	}
+)
+
+func (uc *UseCase) Create(ctx context.Context, req entity.CoinRequest) (*entity.CoinRequest, error) {
+	const op = "usecase.coinrequest.Create"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if req.Amount <= 0 || req.Payer == "" || req.Payer == req.Requester {
+		return nil, fmt.Errorf("%s: %w", op, e.ErrInvalidRequest)
+	}
+
+	if _, err := uc.repoBalance.GetUserBalance(ctx, req.Payer); err != nil {
+		return nil, fmt.Errorf("%s: payer: %w", op, err)
+	}
+
+	req.Status = entity.CoinRequestPending
+	req.ExpiresAt = time.Now().Add(uc.ttl)
+
+	created, err := uc.repoCoinRequest.Add(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return created, nil
+}
+
+// ListIncoming returns requests addressed to the user, newest first.
+func (uc *UseCase) ListIncoming(ctx context.Context, username string) ([]entity.CoinRequest, error) {
+	const op = "usecase.coinrequest.ListIncoming"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	requests, err := uc.repoCoinRequest.ListByPayer(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return withExpiry(requests, time.Now()), nil
+}
+
+// ListOutgoing returns requests created by the user, newest first.
+func (uc *UseCase) ListOutgoing(ctx context.Context, username string) ([]entity.CoinRequest, error) {
+	const op = "usecase.coinrequest.ListOutgoing"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	requests, err := uc.repoCoinRequest.ListByRequester(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return withExpiry(requests, time.Now()), nil
+}
+
+// Accept pays the request from the payer's balance in the same transaction
+// that marks it accepted. A payment that would need admin approval is refused
+// and rolled back: the request would be accepted with no coins moved.
+func (uc *UseCase) Accept(ctx context.Context, username string, id int) (*entity.CoinRequest, error) {
+	const op = "usecase.coinrequest.Accept"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return uc.resolve(ctx, op, username, id, entity.CoinRequestAccepted, func(ctx context.Context, req *entity.CoinRequest) error {
+		pending, err := uc.sendUC.SendCoin(ctx, req.Payer, req.Requester, req.Amount, req.Note)
+		if err != nil {
+			return err
+		}
+
+		if pending != nil {
+			return e.ErrApprovalRequired
+		}
+
+		return nil
+	})
+}
+
+func (uc *UseCase) Decline(ctx context.Context, username string, id int) (*entity.CoinRequest, error) {
+	const op = "usecase.coinrequest.Decline"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return uc.resolve(ctx, op, username, id, entity.CoinRequestDeclined, nil)
+}
+
+func (uc *UseCase) resolve(ctx context.Context,
+	op, username string,
+	id int,
+	status string,
+	action func(ctx context.Context, req *entity.CoinRequest) error,
+) (*entity.CoinRequest, error) {
+	var req *entity.CoinRequest
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+
+		req, err = uc.repoCoinRequest.GetForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		// Only the payer may answer a request.
+		if req.Payer != username {
+			return fmt.Errorf("%s: %w", op, e.ErrNotFound)
+		}
+
+		now := time.Now()
+
+		if req.Status != entity.CoinRequestPending {
+			return fmt.Errorf("%s: %w", op, e.ErrAlreadyResolved)
+		}
+
+		if !now.Before(req.ExpiresAt) {
+			return fmt.Errorf("%s: %w", op, e.ErrExpired)
+		}
+
+		if action != nil {
+			if err = action(ctx, req); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		if err = uc.repoCoinRequest.Resolve(ctx, id, status, now); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		req.Status = status
+		req.ResolvedAt = &now
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// withExpiry reports pending requests past their deadline as expired. Expired
+// rows are never updated in storage: the deadline alone decides.
+func withExpiry(requests []entity.CoinRequest, now time.Time) []entity.CoinRequest {
+	for i := range requests {
+		if requests[i].Status == entity.CoinRequestPending && !now.Before(requests[i].ExpiresAt) {
+			requests[i].Status = entity.CoinRequestExpired
+		}
+	}
+
+	return requests
+}
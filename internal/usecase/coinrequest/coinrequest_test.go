@@ -0,0 +1,79 @@
+package coinrequest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"avito-shop/internal/entity"
+	repomocks "avito-shop/internal/repository/mocks"
+	sendmocks "avito-shop/internal/usecase/send/mocks"
+	e "avito-shop/pkg/errors"
+)
+
+// txManager runs the closures without a database; rollback is the caller's
+// business, so the tests check what was written instead.
+type txManager struct{}
+
+func (txManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (txManager) DoWithSettings(ctx context.Context, _ trm.Settings, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func pendingRequest() *entity.CoinRequest {
+	return &entity.CoinRequest{
+		ID:        7,
+		Requester: "alice",
+		Payer:     "bob",
+		Amount:    500,
+		Note:      "lunch",
+		Status:    entity.CoinRequestPending,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func TestUseCase_Accept_Paid(t *testing.T) {
+	repo := new(repomocks.CoinRequest)
+	sendUC := new(sendmocks.Send)
+
+	repo.On("GetForUpdate", mock.Anything, 7).Return(pendingRequest(), nil)
+	repo.On("Resolve", mock.Anything, 7, entity.CoinRequestAccepted, mock.AnythingOfType("time.Time")).Return(nil)
+	sendUC.On("SendCoin", mock.Anything, "bob", "alice", 500, "lunch").Return(nil, nil)
+
+	uc := &UseCase{repoCoinRequest: repo, sendUC: sendUC, trManager: txManager{}}
+
+	req, err := uc.Accept(context.Background(), "bob", 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.CoinRequestAccepted, req.Status)
+	assert.NotNil(t, req.ResolvedAt)
+
+	repo.AssertExpectations(t)
+	sendUC.AssertExpectations(t)
+}
+
+func TestUseCase_Accept_NeedsApproval(t *testing.T) {
+	repo := new(repomocks.CoinRequest)
+	sendUC := new(sendmocks.Send)
+
+	repo.On("GetForUpdate", mock.Anything, 7).Return(pendingRequest(), nil)
+	sendUC.On("SendCoin", mock.Anything, "bob", "alice", 500, "lunch").
+		Return(&entity.PendingTransfer{ID: 3, FromUser: "bob", ToUser: "alice", Amount: 500}, nil)
+
+	uc := &UseCase{repoCoinRequest: repo, sendUC: sendUC, trManager: txManager{}}
+
+	req, err := uc.Accept(context.Background(), "bob", 7)
+
+	assert.ErrorIs(t, err, e.ErrApprovalRequired)
+	assert.Nil(t, req)
+
+	repo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	sendUC.AssertExpectations(t)
+}
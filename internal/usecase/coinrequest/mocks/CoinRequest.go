@@ -0,0 +1,179 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CoinRequest is an autogenerated mock type for the CoinRequest type
+type CoinRequest struct {
+	mock.Mock
+}
+
+// Accept provides a mock function with given fields: ctx, username, id
+func (_m *CoinRequest) Accept(ctx context.Context, username string, id int) (*entity.CoinRequest, error) {
+	ret := _m.Called(ctx, username, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Accept")
+	}
+
+	var r0 *entity.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*entity.CoinRequest, error)); ok {
+		return rf(ctx, username, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *entity.CoinRequest); ok {
+		r0 = rf(ctx, username, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, username, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, req
+func (_m *CoinRequest) Create(ctx context.Context, req entity.CoinRequest) (*entity.CoinRequest, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *entity.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CoinRequest) (*entity.CoinRequest, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CoinRequest) *entity.CoinRequest); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.CoinRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Decline provides a mock function with given fields: ctx, username, id
+func (_m *CoinRequest) Decline(ctx context.Context, username string, id int) (*entity.CoinRequest, error) {
+	ret := _m.Called(ctx, username, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Decline")
+	}
+
+	var r0 *entity.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*entity.CoinRequest, error)); ok {
+		return rf(ctx, username, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *entity.CoinRequest); ok {
+		r0 = rf(ctx, username, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, username, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListIncoming provides a mock function with given fields: ctx, username
+func (_m *CoinRequest) ListIncoming(ctx context.Context, username string) ([]entity.CoinRequest, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIncoming")
+	}
+
+	var r0 []entity.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.CoinRequest, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.CoinRequest); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOutgoing provides a mock function with given fields: ctx, username
+func (_m *CoinRequest) ListOutgoing(ctx context.Context, username string) ([]entity.CoinRequest, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOutgoing")
+	}
+
+	var r0 []entity.CoinRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.CoinRequest, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.CoinRequest); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.CoinRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCoinRequest creates a new instance of CoinRequest. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCoinRequest(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CoinRequest {
+	mock := &CoinRequest{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
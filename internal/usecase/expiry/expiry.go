@@ -0,0 +1,145 @@
+package expiry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/repository"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
+)
+
+const (
+	// userBatchSize bounds how many users a single ExpireDue call processes.
+	userBatchSize = 100
+)
+
+type UseCase struct {
+	repoLot     LotRepo
+	repoBalance BalanceRepo
+	repoLedger  LedgerRepo
+	trManager   trm.Manager
+	ttl         time.Duration
+}
+
+func New(rl *repository.LotRepo,
+	rb *repository.BalanceRepo,
+	rlg *repository.LedgerRepo,
+	trManager trm.Manager,
+	ttl time.Duration,
+) *UseCase {
+	return &UseCase{
+		repoLot:     rl,
+		repoBalance: rb,
+		repoLedger:  rlg,
+		trManager:   trManager,
+		ttl:         ttl,
+	}
+}
+
+//go:generate mockery --name=Expiry
+
+type (
+	Expiry interface {
+		ExpireDue(ctx context.Context, now time.Time) (int, error)
+	}
+
+	LotRepo interface {
+		NextExpiredUser(ctx context.Context, cutoff time.Time) (string, error)
+		ExpireLots(ctx context.Context, username string, cutoff, now time.Time, limit int) (int, error)
+	}
+
+	BalanceRepo interface {
+		DecreaseBalance(ctx context.Context, username string, amount int) error
+		LockBalances(ctx context.Context, usernames ...string) (map[string]int, error)
+	}
+
+	LedgerRepo interface {
+		Record(ctx context.Context, entry entity.LedgerEntry) error
+	}
+)
+
+// ExpireDue burns coins received more than ttl ago and returns how many users
+// lost coins. Each user is handled in its own transaction.
+func (uc *UseCase) ExpireDue(ctx context.Context, now time.Time) (int, error) {
+	const op = "usecase.expiry.ExpireDue"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	cutoff := now.Add(-uc.ttl)
+	processed := 0
+
+	for processed < userBatchSize {
+		ok, err := uc.expireNext(ctx, cutoff, now)
+		if err != nil {
+			return processed, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !ok {
+			break
+		}
+
+		processed++
+	}
+
+	return processed, nil
+}
+
+func (uc *UseCase) expireNext(ctx context.Context, cutoff, now time.Time) (bool, error) {
+	const op = "usecase.expiry.expireNext"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	found := false
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		username, err := uc.repoLot.NextExpiredUser(ctx, cutoff)
+		if errors.Is(err, e.ErrNotFound) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		found = true
+
+		// The balance is locked before the lots, in the same order as transfers.
+		balances, err := uc.repoBalance.LockBalances(ctx, username)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		// Lots may exceed a balance that was corrected by hand: only what the
+		// balance covers expires, so the lots, the expiry history, the balance
+		// and the ledger all move by the same amount.
+		expired, err := uc.repoLot.ExpireLots(ctx, username, cutoff, now, balances[username])
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if expired == 0 {
+			return nil
+		}
+
+		if err = uc.repoBalance.DecreaseBalance(ctx, username, expired); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = uc.repoLedger.Record(ctx, entity.ExpiryEntry(username, expired)); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
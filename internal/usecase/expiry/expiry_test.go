@@ -0,0 +1,83 @@
+package expiry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"avito-shop/internal/entity"
+	repomocks "avito-shop/internal/repository/mocks"
+	e "avito-shop/pkg/errors"
+)
+
+// txManager runs the closures without a database.
+type txManager struct{}
+
+func (txManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (txManager) DoWithSettings(ctx context.Context, _ trm.Settings, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestUseCase_ExpireDue_BalanceBelowLots(t *testing.T) {
+	lots := new(repomocks.Lot)
+	balance := new(repomocks.Balance)
+	ledger := new(repomocks.Ledger)
+
+	now := time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)
+	ttl := 24 * time.Hour
+	cutoff := now.Add(-ttl)
+
+	lots.On("NextExpiredUser", mock.Anything, cutoff).Return("alice", nil).Once()
+	lots.On("NextExpiredUser", mock.Anything, cutoff).Return("", e.ErrNotFound).Once()
+
+	// 100 coins are due but a manual correction left only 30 in the balance.
+	balance.On("LockBalances", mock.Anything, "alice").Return(map[string]int{"alice": 30}, nil)
+	lots.On("ExpireLots", mock.Anything, "alice", cutoff, now, 30).Return(30, nil)
+	balance.On("DecreaseBalance", mock.Anything, "alice", 30).Return(nil)
+	ledger.On("Record", mock.Anything, entity.ExpiryEntry("alice", 30)).Return(nil)
+
+	uc := &UseCase{repoLot: lots, repoBalance: balance, repoLedger: ledger, trManager: txManager{}, ttl: ttl}
+
+	n, err := uc.ExpireDue(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	lots.AssertExpectations(t)
+	balance.AssertExpectations(t)
+	ledger.AssertExpectations(t)
+}
+
+func TestUseCase_ExpireDue_EmptyBalance(t *testing.T) {
+	lots := new(repomocks.Lot)
+	balance := new(repomocks.Balance)
+	ledger := new(repomocks.Ledger)
+
+	now := time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)
+	ttl := 24 * time.Hour
+	cutoff := now.Add(-ttl)
+
+	lots.On("NextExpiredUser", mock.Anything, cutoff).Return("alice", nil).Once()
+	lots.On("NextExpiredUser", mock.Anything, cutoff).Return("", e.ErrNotFound).Once()
+
+	// The due lots are written off and nothing moves.
+	balance.On("LockBalances", mock.Anything, "alice").Return(map[string]int{"alice": 0}, nil)
+	lots.On("ExpireLots", mock.Anything, "alice", cutoff, now, 0).Return(0, nil)
+
+	uc := &UseCase{repoLot: lots, repoBalance: balance, repoLedger: ledger, trManager: txManager{}, ttl: ttl}
+
+	_, err := uc.ExpireDue(context.Background(), now)
+
+	assert.NoError(t, err)
+
+	lots.AssertExpectations(t)
+	balance.AssertNotCalled(t, "DecreaseBalance", mock.Anything, mock.Anything, mock.Anything)
+	ledger.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,174 @@
+package grant
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/repository"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
+)
+
+type UseCase struct {
+	repoGrant   GrantRepo
+	repoBalance BalanceRepo
+	repoLedger  LedgerRepo
+	repoLot     LotRepo
+	trManager   trm.Manager
+}
+
+func New(rg *repository.GrantRepo,
+	rb *repository.BalanceRepo,
+	rl *repository.LedgerRepo,
+	rlot *repository.LotRepo,
+	trManager trm.Manager,
+) *UseCase {
+	return &UseCase{
+		repoGrant:   rg,
+		repoBalance: rb,
+		repoLedger:  rl,
+		repoLot:     rlot,
+		trManager:   trManager,
+	}
+}
+
+//go:generate mockery --name=Grant
+
+type (
+	Grant interface {
+		Grant(ctx context.Context, g entity.CoinGrant) (*entity.CoinGrant, error)
+		GrantBulk(ctx context.Context, grants []entity.CoinGrant) ([]entity.CoinGrant, error)
+		List(ctx context.Context, username string) ([]entity.CoinGrant, error)
+	}
+
+	GrantRepo interface {
+		Add(ctx context.Context, g entity.CoinGrant) (*entity.CoinGrant, error)
+		ListByUser(ctx context.Context, username string) ([]entity.CoinGrant, error)
+	}
+
+	BalanceRepo interface {
+		DecreaseBalance(ctx context.Context, username string, amount int) error
+		IncreaseBalance(ctx context.Context, username string, amount int) error
+		LockBalances(ctx context.Context, usernames ...string) (map[string]int, error)
+	}
+
+	LedgerRepo interface {
+		Record(ctx context.Context, entry entity.LedgerEntry) error
+	}
+
+	LotRepo interface {
+		AddLot(ctx context.Context, username, source string, amount int) error
+		ConsumeLots(ctx context.Context, username string, amount int) error
+	}
+)
+
+func (uc *UseCase) Grant(ctx context.Context, g entity.CoinGrant) (*entity.CoinGrant, error) {
+	const op = "usecase.grant.Grant"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	granted, err := uc.GrantBulk(ctx, []entity.CoinGrant{g})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &granted[0], nil
+}
+
+// GrantBulk applies all grants in one transaction: if any user is missing or
+// would go below zero after a claw back, nothing is applied.
+func (uc *UseCase) GrantBulk(ctx context.Context, grants []entity.CoinGrant) ([]entity.CoinGrant, error) {
+	const op = "usecase.grant.GrantBulk"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if len(grants) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, e.ErrInvalidRequest)
+	}
+
+	usernames := make([]string, 0, len(grants))
+
+	for _, g := range grants {
+		if g.Amount == 0 || g.Username == "" || g.Reason == "" || g.Author == "" {
+			return nil, fmt.Errorf("%s: %w", op, e.ErrInvalidRequest)
+		}
+
+		usernames = append(usernames, g.Username)
+	}
+
+	granted := make([]entity.CoinGrant, 0, len(grants))
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		granted = granted[:0]
+
+		balances, err := uc.repoBalance.LockBalances(ctx, usernames...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, g := range grants {
+			balances[g.Username] += g.Amount
+			if balances[g.Username] < 0 {
+				return fmt.Errorf("%s: %s: %w", op, g.Username, e.ErrInsufficientFunds)
+			}
+
+			if err = uc.applyBalance(ctx, g); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			added, err := uc.repoGrant.Add(ctx, g)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			if err = uc.repoLedger.Record(ctx, entity.GrantEntry(g.Username, g.Amount)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			granted = append(granted, *added)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return granted, nil
+}
+
+// applyBalance adds a lot for minted coins; claw backs take the oldest coins.
+func (uc *UseCase) applyBalance(ctx context.Context, g entity.CoinGrant) error {
+	if g.Amount > 0 {
+		if err := uc.repoBalance.IncreaseBalance(ctx, g.Username, g.Amount); err != nil {
+			return err
+		}
+
+		return uc.repoLot.AddLot(ctx, g.Username, entity.LotSourceGrant, g.Amount)
+	}
+
+	if err := uc.repoBalance.DecreaseBalance(ctx, g.Username, -g.Amount); err != nil {
+		return err
+	}
+
+	return uc.repoLot.ConsumeLots(ctx, g.Username, -g.Amount)
+}
+
+func (uc *UseCase) List(ctx context.Context, username string) ([]entity.CoinGrant, error) {
+	const op = "usecase.grant.List"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	grants, err := uc.repoGrant.ListByUser(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return grants, nil
+}
@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"avito-shop/internal/repository"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
+)
+
+type UseCase struct {
+	repoHealth HealthRepo
+	// migration is the version of the newest migration the binary ships with.
+	migration uint
+}
+
+func New(rh *repository.HealthRepo, migration uint) *UseCase {
+	return &UseCase{
+		repoHealth: rh,
+		migration:  migration,
+	}
+}
+
+//go:generate mockery --name=Health
+
+type (
+	Health interface {
+		Ping(ctx context.Context) error
+		CheckMigrations(ctx context.Context) error
+	}
+
+	HealthRepo interface {
+		Ping(ctx context.Context) error
+		MigrationVersion(ctx context.Context) (uint, bool, error)
+	}
+)
+
+func (uc *UseCase) Ping(ctx context.Context) error {
+	const op = "usecase.health.Ping"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := uc.repoHealth.Ping(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CheckMigrations fails unless the database is at least at the migration the
+// binary expects and the last migration completed.
+func (uc *UseCase) CheckMigrations(ctx context.Context) error {
+	const op = "usecase.health.CheckMigrations"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	version, dirty, err := uc.repoHealth.MigrationVersion(ctx)
+	if err != nil {
+		if errors.Is(err, e.ErrNotFound) {
+			return fmt.Errorf("%s: no migrations applied, expected %d: %w", op, uc.migration, e.ErrSchemaMismatch)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if dirty {
+		return fmt.Errorf("%s: migration %d is dirty: %w", op, version, e.ErrSchemaMismatch)
+	}
+
+	// A newer schema is fine: in a rolling deploy the new release migrates
+	// while the old pods still serve.
+	if version < uc.migration {
+		return fmt.Errorf("%s: at migration %d, expected %d: %w", op, version, uc.migration, e.ErrSchemaMismatch)
+	}
+
+	return nil
+}
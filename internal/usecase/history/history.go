@@ -0,0 +1,109 @@
+package history
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/repository"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type UseCase struct {
+	repoTransaction TransactionRepo
+}
+
+func New(rt *repository.TransactionRepo) *UseCase {
+	return &UseCase{
+		repoTransaction: rt,
+	}
+}
+
+//go:generate mockery --name=History
+
+type (
+	History interface {
+		List(ctx context.Context, f entity.TransactionFilter, cursor string) (*entity.TransactionPage, error)
+	}
+
+	TransactionRepo interface {
+		ListTransactions(ctx context.Context, f entity.TransactionFilter) ([]entity.CoinTransaction, error)
+	}
+)
+
+// List returns one page of the user's transactions. The cursor is the
+// NextCursor of the previous page; an empty cursor starts from the newest.
+func (uc *UseCase) List(ctx context.Context, f entity.TransactionFilter, cursor string) (*entity.TransactionPage, error) {
+	const op = "usecase.history.List"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	switch {
+	case f.Limit == 0:
+		f.Limit = defaultPageSize
+	case f.Limit < 0 || f.Limit > maxPageSize:
+		return nil, fmt.Errorf("%s: %w", op, e.ErrInvalidRequest)
+	}
+
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return nil, fmt.Errorf("%s: %w", op, e.ErrInvalidRequest)
+	}
+
+	if cursor != "" {
+		before, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, e.ErrInvalidRequest)
+		}
+
+		f.Before = before
+	}
+
+	// One extra row tells whether there is a next page.
+	limit := f.Limit
+	f.Limit++
+
+	txns, err := uc.repoTransaction.ListTransactions(ctx, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	page := &entity.TransactionPage{Transactions: txns}
+
+	if len(txns) > limit {
+		page.Transactions = txns[:limit]
+		page.NextCursor = encodeCursor(txns[limit-1].ID)
+	}
+
+	if page.Transactions == nil {
+		page.Transactions = []entity.CoinTransaction{}
+	}
+
+	return page, nil
+}
+
+func encodeCursor(id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(id)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := strconv.Atoi(string(raw))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// History is an autogenerated mock type for the History type
+type History struct {
+	mock.Mock
+}
+
+// List provides a mock function with given fields: ctx, f, cursor
+func (_m *History) List(ctx context.Context, f entity.TransactionFilter, cursor string) (*entity.TransactionPage, error) {
+	ret := _m.Called(ctx, f, cursor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *entity.TransactionPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.TransactionFilter, string) (*entity.TransactionPage, error)); ok {
+		return rf(ctx, f, cursor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.TransactionFilter, string) *entity.TransactionPage); ok {
+		r0 = rf(ctx, f, cursor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.TransactionPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.TransactionFilter, string) error); ok {
+		r1 = rf(ctx, f, cursor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewHistory creates a new instance of History. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHistory(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *History {
+	mock := &History{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
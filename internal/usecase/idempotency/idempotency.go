@@ -0,0 +1,100 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/repository"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
+)
+
+type UseCase struct {
+	repoIdempotency IdempotencyRepo
+	trManager       trm.Manager
+}
+
+func New(ri *repository.IdempotencyRepo, trManager trm.Manager) *UseCase {
+	return &UseCase{
+		repoIdempotency: ri,
+		trManager:       trManager,
+	}
+}
+
+// Operation performs the request and returns the response to remember.
+// It runs inside the same transaction as the stored key, so use cases called
+// from it join that transaction.
+type Operation func(ctx context.Context) (statusCode int, response []byte, err error)
+
+//go:generate mockery --name=Idempotency
+
+type (
+	Idempotency interface {
+		Do(ctx context.Context, in entity.IdempotencyKey, fn Operation) (*entity.IdempotencyKey, bool, error)
+	}
+
+	IdempotencyRepo interface {
+		Reserve(ctx context.Context, key entity.IdempotencyKey) (bool, error)
+		Get(ctx context.Context, username, key string) (*entity.IdempotencyKey, error)
+		Complete(ctx context.Context, key entity.IdempotencyKey) error
+	}
+)
+
+// Do runs fn once per (username, key). Retries with the same request hash get
+// the stored response back with replayed set to true.
+func (uc *UseCase) Do(ctx context.Context, in entity.IdempotencyKey, fn Operation) (*entity.IdempotencyKey, bool, error) {
+	const op = "usecase.idempotency.Do"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	var (
+		result   *entity.IdempotencyKey
+		replayed bool
+	)
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		reserved, err := uc.repoIdempotency.Reserve(ctx, in)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !reserved {
+			stored, err := uc.repoIdempotency.Get(ctx, in.Username, in.Key)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			if stored.RequestHash != in.RequestHash {
+				return fmt.Errorf("%s: %w", op, e.ErrIdempotencyKeyReused)
+			}
+
+			result, replayed = stored, true
+
+			return nil
+		}
+
+		statusCode, response, err := fn(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		in.StatusCode, in.Response = statusCode, response
+
+		if err = uc.repoIdempotency.Complete(ctx, in); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		result = &in
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return result, replayed, nil
+}
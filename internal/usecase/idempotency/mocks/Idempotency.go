@@ -0,0 +1,67 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	idempotency "avito-shop/internal/usecase/idempotency"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Idempotency is an autogenerated mock type for the Idempotency type
+type Idempotency struct {
+	mock.Mock
+}
+
+// Do provides a mock function with given fields: ctx, in, fn
+func (_m *Idempotency) Do(ctx context.Context, in entity.IdempotencyKey, fn idempotency.Operation) (*entity.IdempotencyKey, bool, error) {
+	ret := _m.Called(ctx, in, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Do")
+	}
+
+	var r0 *entity.IdempotencyKey
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.IdempotencyKey, idempotency.Operation) (*entity.IdempotencyKey, bool, error)); ok {
+		return rf(ctx, in, fn)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.IdempotencyKey, idempotency.Operation) *entity.IdempotencyKey); ok {
+		r0 = rf(ctx, in, fn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.IdempotencyKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.IdempotencyKey, idempotency.Operation) bool); ok {
+		r1 = rf(ctx, in, fn)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.IdempotencyKey, idempotency.Operation) error); ok {
+		r2 = rf(ctx, in, fn)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewIdempotency creates a new instance of Idempotency. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotency(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Idempotency {
+	mock := &Idempotency{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -2,30 +2,41 @@ package info
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
 
 	"avito-shop/internal/entity"
 	"avito-shop/internal/repository"
+	"avito-shop/pkg/tracing"
+)
+
+const (
+	// recentHistorySize bounds each direction of the history in /api/info;
+	// the full history is paginated by /api/transactions.
+	recentHistorySize = 50
 )
 
 type UseCase struct {
 	repoBalance     BalanceRepo
 	repoInventory   InventoryRepo
 	repoTransaction TransactionRepo
-	trManager       *manager.Manager
+	repoLot         LotRepo
+	trManager       trm.Manager
 }
 
 func New(
 	repoBalance *repository.BalanceRepo,
 	repoInventory *repository.InventoryRepo,
 	repoTransaction *repository.TransactionRepo,
-	trManager *manager.Manager,
+	repoLot *repository.LotRepo,
+	trManager trm.Manager,
 ) *UseCase {
 	return &UseCase{
 		repoBalance:     repoBalance,
 		repoInventory:   repoInventory,
 		repoTransaction: repoTransaction,
+		repoLot:         repoLot,
 		trManager:       trManager,
 	}
 }
@@ -35,6 +46,7 @@ func New(
 type (
 	Info interface {
 		GetInfo(ctx context.Context, username string) (*entity.Info, error)
+		GetAggregatedInfo(ctx context.Context, username string) (*entity.AggregatedInfo, error)
 	}
 
 	BalanceRepo interface {
@@ -47,19 +59,30 @@ type (
 	}
 
 	TransactionRepo interface {
-		GetSentTransactions(ctx context.Context, username string) ([]entity.SentTransaction, error)
-		GetReceivedTransactions(ctx context.Context, username string) ([]entity.ReceivedTransaction, error)
+		GetSentTransactions(ctx context.Context, username string, limit int) ([]entity.SentTransaction, error)
+		GetReceivedTransactions(ctx context.Context, username string, limit int) ([]entity.ReceivedTransaction, error)
+		GetSentSummary(ctx context.Context, username string) ([]entity.SentSummary, error)
+		GetReceivedSummary(ctx context.Context, username string) ([]entity.ReceivedSummary, error)
+	}
+
+	LotRepo interface {
+		ListExpiries(ctx context.Context, username string) ([]entity.ExpiredCoins, error)
+		GetExpirySummary(ctx context.Context, username string) (entity.ExpirySummary, error)
 	}
 )
 
 func (uc *UseCase) GetInfo(ctx context.Context, username string) (*entity.Info, error) {
-	//const op = "usecase.info.GetInfo"
+	const op = "usecase.info.GetInfo"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	var (
 		balance      int
 		inventory    []entity.InventoryItem
 		sentTxns     []entity.SentTransaction
 		receivedTxns []entity.ReceivedTransaction
+		expired      []entity.ExpiredCoins
 		err          error
 	)
 
@@ -74,12 +97,17 @@ func (uc *UseCase) GetInfo(ctx context.Context, username string) (*entity.Info,
 			return err
 		}
 
-		sentTxns, err = uc.repoTransaction.GetSentTransactions(ctx, username)
+		sentTxns, err = uc.repoTransaction.GetSentTransactions(ctx, username, recentHistorySize)
+		if err != nil {
+			return err
+		}
+
+		receivedTxns, err = uc.repoTransaction.GetReceivedTransactions(ctx, username, recentHistorySize)
 		if err != nil {
 			return err
 		}
 
-		receivedTxns, err = uc.repoTransaction.GetReceivedTransactions(ctx, username)
+		expired, err = uc.repoLot.ListExpiries(ctx, username)
 		if err != nil {
 			return err
 		}
@@ -87,7 +115,7 @@ func (uc *UseCase) GetInfo(ctx context.Context, username string) (*entity.Info,
 		return nil
 	})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return &entity.Info{
@@ -96,6 +124,67 @@ func (uc *UseCase) GetInfo(ctx context.Context, username string) (*entity.Info,
 		CoinHistory: entity.CoinHistory{
 			Received: receivedTxns,
 			Sent:     sentTxns,
+			Expired:  expired,
+		},
+	}, nil
+}
+
+// GetAggregatedInfo is GetInfo with the whole history grouped by counterparty
+// instead of the latest rows.
+func (uc *UseCase) GetAggregatedInfo(ctx context.Context, username string) (*entity.AggregatedInfo, error) {
+	const op = "usecase.info.GetAggregatedInfo"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	var (
+		balance   int
+		inventory []entity.InventoryItem
+		sent      []entity.SentSummary
+		received  []entity.ReceivedSummary
+		expired   entity.ExpirySummary
+		err       error
+	)
+
+	err = uc.trManager.Do(ctx, func(ctx context.Context) error {
+		balance, err = uc.repoBalance.GetUserBalance(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		inventory, err = uc.repoInventory.GetInventory(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		sent, err = uc.repoTransaction.GetSentSummary(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		received, err = uc.repoTransaction.GetReceivedSummary(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		expired, err = uc.repoLot.GetExpirySummary(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &entity.AggregatedInfo{
+		Coins:     balance,
+		Inventory: inventory,
+		CoinHistory: entity.AggregatedCoinHistory{
+			Received: received,
+			Sent:     sent,
+			Expired:  expired,
 		},
 	}, nil
 }
@@ -14,6 +14,36 @@ type Info struct {
 	mock.Mock
 }
 
+// GetAggregatedInfo provides a mock function with given fields: ctx, username
+func (_m *Info) GetAggregatedInfo(ctx context.Context, username string) (*entity.AggregatedInfo, error) {
+	ret := _m.Called(ctx, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAggregatedInfo")
+	}
+
+	var r0 *entity.AggregatedInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.AggregatedInfo, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.AggregatedInfo); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AggregatedInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetInfo provides a mock function with given fields: ctx, username
 func (_m *Info) GetInfo(ctx context.Context, username string) (*entity.Info, error) {
 	ret := _m.Called(ctx, username)
@@ -0,0 +1,97 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+
+	"avito-shop/internal/repository"
+	"avito-shop/pkg/tracing"
+)
+
+type UseCase struct {
+	repoLedger  LedgerRepo
+	repoBalance BalanceRepo
+	trManager   trm.Manager
+}
+
+func New(rl *repository.LedgerRepo, rb *repository.BalanceRepo, trManager trm.Manager) *UseCase {
+	return &UseCase{
+		repoLedger:  rl,
+		repoBalance: rb,
+		trManager:   trManager,
+	}
+}
+
+//go:generate mockery --name=Ledger
+
+type (
+	Ledger interface {
+		RecomputeBalances(ctx context.Context) (int, error)
+	}
+
+	LedgerRepo interface {
+		UserBalances(ctx context.Context) (map[string]int, error)
+	}
+
+	BalanceRepo interface {
+		LockBalances(ctx context.Context, usernames ...string) (map[string]int, error)
+		ListBalances(ctx context.Context) (map[string]int, error)
+		SetBalance(ctx context.Context, username string, coins int) error
+	}
+)
+
+// RecomputeBalances rewrites the Balance cache from the ledger and returns the
+// number of users whose cached balance was wrong. All balance rows are locked
+// first so that no transfer commits between reading the ledger and writing.
+func (uc *UseCase) RecomputeBalances(ctx context.Context) (int, error) {
+	const op = "usecase.ledger.RecomputeBalances"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	fixed := 0
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		fixed = 0
+
+		cached, err := uc.repoBalance.ListBalances(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		usernames := make([]string, 0, len(cached))
+		for username := range cached {
+			usernames = append(usernames, username)
+		}
+
+		if cached, err = uc.repoBalance.LockBalances(ctx, usernames...); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		expected, err := uc.repoLedger.UserBalances(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for username, coins := range cached {
+			if expected[username] == coins {
+				continue
+			}
+
+			if err = uc.repoBalance.SetBalance(ctx, username, expected[username]); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			fixed++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return fixed, nil
+}
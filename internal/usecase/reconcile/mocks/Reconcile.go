@@ -0,0 +1,61 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Reconcile is an autogenerated mock type for the Reconcile type
+type Reconcile struct {
+	mock.Mock
+}
+
+// Run provides a mock function with given fields: ctx, now
+func (_m *Reconcile) Run(ctx context.Context, now time.Time) (*entity.DriftReport, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Run")
+	}
+
+	var r0 *entity.DriftReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (*entity.DriftReport, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) *entity.DriftReport); ok {
+		r0 = rf(ctx, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.DriftReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReconcile creates a new instance of Reconcile. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReconcile(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Reconcile {
+	mock := &Reconcile{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
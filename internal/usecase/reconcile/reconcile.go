@@ -0,0 +1,86 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/repository"
+	"avito-shop/pkg/tracing"
+)
+
+type UseCase struct {
+	repoReconciliation ReconciliationRepo
+	trManager          trm.Manager
+}
+
+func New(rr *repository.ReconciliationRepo, trManager trm.Manager) *UseCase {
+	return &UseCase{
+		repoReconciliation: rr,
+		trManager:          trManager,
+	}
+}
+
+//go:generate mockery --name=Reconcile
+
+type (
+	Reconcile interface {
+		Run(ctx context.Context, now time.Time) (*entity.DriftReport, error)
+	}
+
+	ReconciliationRepo interface {
+		CheckBalances(ctx context.Context) ([]entity.BalanceCheck, error)
+		SaveReport(ctx context.Context, report entity.DriftReport) (int, error)
+	}
+)
+
+// Run compares every balance with the one explained by the user's history and
+// stores the result. Balances are only reported, never corrected.
+func (uc *UseCase) Run(ctx context.Context, now time.Time) (*entity.DriftReport, error) {
+	const op = "usecase.reconcile.Run"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	report := entity.DriftReport{
+		CheckedAt: now,
+		Drifts:    []entity.BalanceCheck{},
+	}
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		checks, err := uc.repoReconciliation.CheckBalances(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		report.Users = len(checks)
+
+		for _, c := range checks {
+			drift := c.Drift()
+			if drift == 0 {
+				continue
+			}
+
+			if drift < 0 {
+				drift = -drift
+			}
+
+			report.TotalDrift += drift
+			report.Drifts = append(report.Drifts, c)
+		}
+
+		if report.ID, err = uc.repoReconciliation.SaveReport(ctx, report); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
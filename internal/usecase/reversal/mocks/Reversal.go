@@ -0,0 +1,59 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Reversal is an autogenerated mock type for the Reversal type
+type Reversal struct {
+	mock.Mock
+}
+
+// Reverse provides a mock function with given fields: ctx, admin, id, reason, allowPartial
+func (_m *Reversal) Reverse(ctx context.Context, admin string, id int, reason string, allowPartial bool) (*entity.Reversal, error) {
+	ret := _m.Called(ctx, admin, id, reason, allowPartial)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reverse")
+	}
+
+	var r0 *entity.Reversal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, string, bool) (*entity.Reversal, error)); ok {
+		return rf(ctx, admin, id, reason, allowPartial)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, string, bool) *entity.Reversal); ok {
+		r0 = rf(ctx, admin, id, reason, allowPartial)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Reversal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, string, bool) error); ok {
+		r1 = rf(ctx, admin, id, reason, allowPartial)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReversal creates a new instance of Reversal. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReversal(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Reversal {
+	mock := &Reversal{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
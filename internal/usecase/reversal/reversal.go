@@ -0,0 +1,164 @@
+package reversal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/repository"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
+)
+
+type UseCase struct {
+	repoTransaction TransactionRepo
+	repoBalance     BalanceRepo
+	repoLedger      LedgerRepo
+	repoLot         LotRepo
+	trManager       trm.Manager
+}
+
+func New(rt *repository.TransactionRepo,
+	rb *repository.BalanceRepo,
+	rl *repository.LedgerRepo,
+	rlot *repository.LotRepo,
+	trManager trm.Manager,
+) *UseCase {
+	return &UseCase{
+		repoTransaction: rt,
+		repoBalance:     rb,
+		repoLedger:      rl,
+		repoLot:         rlot,
+		trManager:       trManager,
+	}
+}
+
+//go:generate mockery --name=Reversal
+
+type (
+	Reversal interface {
+		Reverse(ctx context.Context, admin string, id int, reason string, allowPartial bool) (*entity.Reversal, error)
+	}
+
+	TransactionRepo interface {
+		GetForUpdate(ctx context.Context, id int) (*entity.CoinTransaction, error)
+		AddReversal(ctx context.Context, txn entity.CoinTransaction, reversedBy string, reversedAt time.Time) (int, error)
+	}
+
+	BalanceRepo interface {
+		DecreaseBalance(ctx context.Context, username string, amount int) error
+		IncreaseBalance(ctx context.Context, username string, amount int) error
+		LockBalances(ctx context.Context, usernames ...string) (map[string]int, error)
+	}
+
+	LedgerRepo interface {
+		Record(ctx context.Context, entry entity.LedgerEntry) error
+	}
+
+	LotRepo interface {
+		AddLot(ctx context.Context, username, source string, amount int) error
+		ConsumeLots(ctx context.Context, username string, amount int) error
+	}
+)
+
+// Reverse moves the coins of a transfer back to its sender with a compensating
+// transaction. If the recipient has already spent them, the reversal fails
+// unless allowPartial is set, in which case whatever the recipient holds is
+// taken back. A transaction is reversed at most once and reversals themselves
+// cannot be reversed.
+func (uc *UseCase) Reverse(ctx context.Context,
+	admin string,
+	id int,
+	reason string,
+	allowPartial bool,
+) (*entity.Reversal, error) {
+	const op = "usecase.reversal.Reverse"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if reason == "" {
+		return nil, fmt.Errorf("%s: %w", op, e.ErrInvalidRequest)
+	}
+
+	var result *entity.Reversal
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		txn, err := uc.repoTransaction.GetForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if txn.ReversalOf != nil {
+			return fmt.Errorf("%s: %w", op, e.ErrInvalidRequest)
+		}
+
+		if txn.ReversedAt != nil {
+			return fmt.Errorf("%s: %w", op, e.ErrAlreadyResolved)
+		}
+
+		balances, err := uc.repoBalance.LockBalances(ctx, txn.FromUser, txn.ToUser)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		amount := txn.Amount
+		if balances[txn.ToUser] < amount {
+			if !allowPartial || balances[txn.ToUser] == 0 {
+				return fmt.Errorf("%s: %w", op, e.ErrInsufficientFunds)
+			}
+
+			amount = balances[txn.ToUser]
+		}
+
+		if err = uc.repoBalance.DecreaseBalance(ctx, txn.ToUser, amount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = uc.repoBalance.IncreaseBalance(ctx, txn.FromUser, amount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = uc.repoLot.ConsumeLots(ctx, txn.ToUser, amount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = uc.repoLot.AddLot(ctx, txn.FromUser, entity.LotSourceReversal, amount); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		reversalID, err := uc.repoTransaction.AddReversal(ctx, entity.CoinTransaction{
+			FromUser:   txn.ToUser,
+			ToUser:     txn.FromUser,
+			Amount:     amount,
+			Message:    fmt.Sprintf("Reversal of transaction #%d: %s", txn.ID, reason),
+			ReversalOf: &txn.ID,
+		}, admin, time.Now())
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = uc.repoLedger.Record(ctx, entity.ReversalEntry(txn.ID, txn.FromUser, txn.ToUser, amount)); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		result = &entity.Reversal{
+			TransactionID: txn.ID,
+			ReversalID:    reversalID,
+			Amount:        amount,
+			Shortfall:     txn.Amount - amount,
+			Reason:        reason,
+			ReversedBy:    admin,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,351 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/settings"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/repository"
+	"avito-shop/internal/usecase/send"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
+)
+
+const (
+	// dueBatchSize bounds how many transfers a single RunDue call executes.
+	dueBatchSize = 100
+)
+
+type UseCase struct {
+	repoSchedule ScheduleRepo
+	repoBalance  BalanceRepo
+	sendUC       send.Send
+	trManager    trm.Manager
+}
+
+func New(rs *repository.ScheduleRepo,
+	rb *repository.BalanceRepo,
+	sendUC send.Send,
+	trManager trm.Manager,
+) *UseCase {
+	return &UseCase{
+		repoSchedule: rs,
+		repoBalance:  rb,
+		sendUC:       sendUC,
+		trManager:    trManager,
+	}
+}
+
+//go:generate mockery --name=Schedule
+
+type (
+	Schedule interface {
+		Create(ctx context.Context, st entity.ScheduledTransfer) (*entity.ScheduledTransfer, error)
+		List(ctx context.Context, username string) ([]entity.ScheduledTransfer, error)
+		Get(ctx context.Context, username string, id int) (*entity.ScheduledTransfer, error)
+		Update(ctx context.Context, st entity.ScheduledTransfer) (*entity.ScheduledTransfer, error)
+		Delete(ctx context.Context, username string, id int) error
+		RunDue(ctx context.Context, now time.Time) (int, error)
+	}
+
+	ScheduleRepo interface {
+		Add(ctx context.Context, st entity.ScheduledTransfer) (int, error)
+		Get(ctx context.Context, id int) (*entity.ScheduledTransfer, error)
+		ListByUser(ctx context.Context, username string) ([]entity.ScheduledTransfer, error)
+		Update(ctx context.Context, st entity.ScheduledTransfer) error
+		Delete(ctx context.Context, id int) error
+		ClaimDue(ctx context.Context, now time.Time) (*entity.ScheduledTransfer, error)
+	}
+
+	BalanceRepo interface {
+		GetUserBalance(ctx context.Context, username string) (int, error)
+	}
+)
+
+func (uc *UseCase) Create(ctx context.Context, st entity.ScheduledTransfer) (*entity.ScheduledTransfer, error) {
+	const op = "usecase.schedule.Create"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := validate(st); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	st.StartsAt = st.NextRunAt
+	st.Active = true
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := uc.repoBalance.GetUserBalance(ctx, st.ToUser); err != nil {
+			return fmt.Errorf("%s: recipient: %w", op, err)
+		}
+
+		id, err := uc.repoSchedule.Add(ctx, st)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		st.ID = id
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &st, nil
+}
+
+func (uc *UseCase) List(ctx context.Context, username string) ([]entity.ScheduledTransfer, error) {
+	const op = "usecase.schedule.List"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	transfers, err := uc.repoSchedule.ListByUser(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return transfers, nil
+}
+
+func (uc *UseCase) Get(ctx context.Context, username string, id int) (*entity.ScheduledTransfer, error) {
+	const op = "usecase.schedule.Get"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	st, err := uc.repoSchedule.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Someone else's schedule is reported as missing rather than forbidden.
+	if st.FromUser != username {
+		return nil, fmt.Errorf("%s: %w", op, e.ErrNotFound)
+	}
+
+	return st, nil
+}
+
+// Update replaces the editable fields of an existing schedule and
+// reactivates it, clearing the recorded failures.
+func (uc *UseCase) Update(ctx context.Context, in entity.ScheduledTransfer) (*entity.ScheduledTransfer, error) {
+	const op = "usecase.schedule.Update"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := validate(in); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var st *entity.ScheduledTransfer
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+
+		st, err = uc.Get(ctx, in.FromUser, in.ID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if _, err = uc.repoBalance.GetUserBalance(ctx, in.ToUser); err != nil {
+			return fmt.Errorf("%s: recipient: %w", op, err)
+		}
+
+		st.ToUser = in.ToUser
+		st.Amount = in.Amount
+		st.Message = in.Message
+		st.Repeat = in.Repeat
+		st.NextRunAt = in.NextRunAt
+		st.StartsAt = in.NextRunAt
+		st.Active = true
+		st.LastError = ""
+		st.Failures = 0
+
+		if err = uc.repoSchedule.Update(ctx, *st); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+func (uc *UseCase) Delete(ctx context.Context, username string, id int) error {
+	const op = "usecase.schedule.Delete"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return uc.trManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := uc.Get(ctx, username, id); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := uc.repoSchedule.Delete(ctx, id); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+}
+
+// RunDue executes transfers whose time has come and returns how many were
+// processed. A failed transfer is recorded on the schedule and does not stop
+// the others.
+func (uc *UseCase) RunDue(ctx context.Context, now time.Time) (int, error) {
+	const op = "usecase.schedule.RunDue"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	processed := 0
+
+	for processed < dueBatchSize {
+		ok, err := uc.runNext(ctx, now)
+		if err != nil {
+			return processed, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !ok {
+			break
+		}
+
+		processed++
+	}
+
+	return processed, nil
+}
+
+func (uc *UseCase) runNext(ctx context.Context, now time.Time) (bool, error) {
+	const op = "usecase.schedule.runNext"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	claimed := false
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		st, err := uc.repoSchedule.ClaimDue(ctx, now)
+		if errors.Is(err, e.ErrNotFound) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		claimed = true
+
+		// The transfer runs in a savepoint so that its failure can be recorded
+		// on the schedule without rolling back the claim.
+		nested := settings.Must(settings.WithPropagation(trm.PropagationNested))
+
+		// Nobody waits on a scheduled run, so a transfer that would be held for
+		// approval fails the run and is rolled back with the savepoint rather
+		// than counting as done with no coins moved.
+		sendErr := uc.trManager.DoWithSettings(ctx, nested, func(ctx context.Context) error {
+			pending, err := uc.sendUC.SendCoin(ctx, st.FromUser, st.ToUser, st.Amount, st.Message)
+			if err != nil {
+				return err
+			}
+
+			if pending != nil {
+				return e.ErrApprovalRequired
+			}
+
+			return nil
+		})
+
+		st.LastRunAt = &now
+		if sendErr != nil {
+			st.LastError = sendErr.Error()
+			st.Failures++
+		} else {
+			st.LastError = ""
+		}
+
+		st.NextRunAt = nextRun(st.StartsAt, st.NextRunAt, st.Repeat, now)
+		st.Active = st.Repeat != entity.RepeatNone
+
+		if err = uc.repoSchedule.Update(ctx, *st); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return claimed, nil
+}
+
+// nextRun advances from the previous planned time so recurring transfers keep
+// their day and time, skipping occurrences missed while the service was down.
+// Monthly runs are counted from start rather than prev: a transfer starting on
+// the 31st runs on the last day of shorter months and is back on the 31st
+// after them.
+func nextRun(start, prev time.Time, repeat string, now time.Time) time.Time {
+	next := prev
+	months := monthsBetween(start, prev)
+
+	for !next.After(now) {
+		switch repeat {
+		case entity.RepeatDaily:
+			next = next.AddDate(0, 0, 1)
+		case entity.RepeatWeekly:
+			next = next.AddDate(0, 0, 7)
+		case entity.RepeatMonthly:
+			months++
+			next = addMonths(start, months)
+		default:
+			return prev
+		}
+	}
+
+	return next
+}
+
+// addMonths moves t by n calendar months, clamping the day to the length of
+// the resulting month instead of overflowing into the next one.
+func addMonths(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+
+	first := time.Date(year, month+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+
+	return first.AddDate(0, 0, day-1)
+}
+
+// monthsBetween counts the calendar months from start to t.
+func monthsBetween(start, t time.Time) int {
+	t = t.In(start.Location())
+
+	return (t.Year()-start.Year())*12 + int(t.Month()-start.Month())
+}
+
+func validate(st entity.ScheduledTransfer) error {
+	if st.Amount <= 0 || st.ToUser == "" || st.ToUser == st.FromUser || st.NextRunAt.IsZero() {
+		return e.ErrInvalidRequest
+	}
+
+	switch st.Repeat {
+	case entity.RepeatNone, entity.RepeatDaily, entity.RepeatWeekly, entity.RepeatMonthly:
+		return nil
+	default:
+		return e.ErrInvalidRequest
+	}
+}
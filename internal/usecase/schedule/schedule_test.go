@@ -0,0 +1,133 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"avito-shop/internal/entity"
+	repomocks "avito-shop/internal/repository/mocks"
+	sendmocks "avito-shop/internal/usecase/send/mocks"
+	e "avito-shop/pkg/errors"
+)
+
+// txManager runs the closures without a database.
+type txManager struct{}
+
+func (txManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (txManager) DoWithSettings(ctx context.Context, _ trm.Settings, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func dueTransfer(at time.Time) *entity.ScheduledTransfer {
+	return &entity.ScheduledTransfer{
+		ID:        4,
+		FromUser:  "alice",
+		ToUser:    "bob",
+		Amount:    300,
+		Message:   "rent",
+		Repeat:    entity.RepeatDaily,
+		NextRunAt: at,
+		Active:    true,
+	}
+}
+
+func TestUseCase_RunDue_Sent(t *testing.T) {
+	repo := new(repomocks.Schedule)
+	sendUC := new(sendmocks.Send)
+
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	repo.On("ClaimDue", mock.Anything, now).Return(dueTransfer(now), nil).Once()
+	repo.On("ClaimDue", mock.Anything, now).Return(nil, e.ErrNotFound).Once()
+	sendUC.On("SendCoin", mock.Anything, "alice", "bob", 300, "rent").Return(nil, nil)
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(st entity.ScheduledTransfer) bool {
+		return st.LastError == "" && st.Failures == 0 &&
+			st.NextRunAt.Equal(now.AddDate(0, 0, 1)) && st.Active
+	})).Return(nil)
+
+	uc := &UseCase{repoSchedule: repo, sendUC: sendUC, trManager: txManager{}}
+
+	n, err := uc.RunDue(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	repo.AssertExpectations(t)
+	sendUC.AssertExpectations(t)
+}
+
+func TestUseCase_RunDue_NeedsApproval(t *testing.T) {
+	repo := new(repomocks.Schedule)
+	sendUC := new(sendmocks.Send)
+
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	repo.On("ClaimDue", mock.Anything, now).Return(dueTransfer(now), nil).Once()
+	repo.On("ClaimDue", mock.Anything, now).Return(nil, e.ErrNotFound).Once()
+	sendUC.On("SendCoin", mock.Anything, "alice", "bob", 300, "rent").
+		Return(&entity.PendingTransfer{ID: 9, FromUser: "alice", ToUser: "bob", Amount: 300}, nil)
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(st entity.ScheduledTransfer) bool {
+		return st.LastError == e.ErrApprovalRequired.Error() && st.Failures == 1
+	})).Return(nil)
+
+	uc := &UseCase{repoSchedule: repo, sendUC: sendUC, trManager: txManager{}}
+
+	n, err := uc.RunDue(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	repo.AssertExpectations(t)
+	sendUC.AssertExpectations(t)
+}
+
+func TestNextRun_MonthEnd(t *testing.T) {
+	start := time.Date(2030, time.January, 31, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		prev time.Time
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "short month",
+			prev: start,
+			now:  start,
+			want: time.Date(2030, time.February, 28, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "back to the anchor day",
+			prev: time.Date(2030, time.February, 28, 10, 0, 0, 0, time.UTC),
+			now:  time.Date(2030, time.February, 28, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2030, time.March, 31, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "missed runs",
+			prev: start,
+			now:  time.Date(2030, time.April, 15, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2030, time.April, 30, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "leap year",
+			prev: time.Date(2031, time.December, 31, 10, 0, 0, 0, time.UTC),
+			now:  time.Date(2032, time.January, 31, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2032, time.February, 29, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextRun(start, tt.prev, entity.RepeatMonthly, tt.now)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
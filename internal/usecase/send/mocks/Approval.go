@@ -0,0 +1,119 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Approval is an autogenerated mock type for the Approval type
+type Approval struct {
+	mock.Mock
+}
+
+// Approve provides a mock function with given fields: ctx, admin, id
+func (_m *Approval) Approve(ctx context.Context, admin string, id int) (*entity.PendingTransfer, error) {
+	ret := _m.Called(ctx, admin, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Approve")
+	}
+
+	var r0 *entity.PendingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*entity.PendingTransfer, error)); ok {
+		return rf(ctx, admin, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *entity.PendingTransfer); ok {
+		r0 = rf(ctx, admin, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.PendingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, admin, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPending provides a mock function with given fields: ctx
+func (_m *Approval) ListPending(ctx context.Context) ([]entity.PendingTransfer, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPending")
+	}
+
+	var r0 []entity.PendingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.PendingTransfer, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.PendingTransfer); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PendingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reject provides a mock function with given fields: ctx, admin, id
+func (_m *Approval) Reject(ctx context.Context, admin string, id int) (*entity.PendingTransfer, error) {
+	ret := _m.Called(ctx, admin, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reject")
+	}
+
+	var r0 *entity.PendingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*entity.PendingTransfer, error)); ok {
+		return rf(ctx, admin, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *entity.PendingTransfer); ok {
+		r0 = rf(ctx, admin, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.PendingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, admin, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewApproval creates a new instance of Approval. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewApproval(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Approval {
+	mock := &Approval{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
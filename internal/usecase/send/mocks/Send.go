@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	entity "avito-shop/internal/entity"
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,17 +14,47 @@ type Send struct {
 	mock.Mock
 }
 
-// SendCoin provides a mock function with given fields: ctx, fromUser, toUser, amount
-func (_m *Send) SendCoin(ctx context.Context, fromUser string, toUser string, amount int) error {
-	ret := _m.Called(ctx, fromUser, toUser, amount)
+// SendCoin provides a mock function with given fields: ctx, fromUser, toUser, amount, message
+func (_m *Send) SendCoin(ctx context.Context, fromUser string, toUser string, amount int, message string) (*entity.PendingTransfer, error) {
+	ret := _m.Called(ctx, fromUser, toUser, amount, message)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendCoin")
 	}
 
+	var r0 *entity.PendingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, string) (*entity.PendingTransfer, error)); ok {
+		return rf(ctx, fromUser, toUser, amount, message)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, string) *entity.PendingTransfer); ok {
+		r0 = rf(ctx, fromUser, toUser, amount, message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.PendingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, string) error); ok {
+		r1 = rf(ctx, fromUser, toUser, amount, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendCoinBatch provides a mock function with given fields: ctx, fromUser, transfers
+func (_m *Send) SendCoinBatch(ctx context.Context, fromUser string, transfers []entity.Transfer) error {
+	ret := _m.Called(ctx, fromUser, transfers)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendCoinBatch")
+	}
+
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) error); ok {
-		r0 = rf(ctx, fromUser, toUser, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []entity.Transfer) error); ok {
+		r0 = rf(ctx, fromUser, transfers)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3,91 +3,439 @@ package send
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
+	"unicode"
 
-	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"golang.org/x/exp/slog"
 
 	"avito-shop/internal/entity"
 	"avito-shop/internal/repository"
 	"avito-shop/pkg/errors"
+	"avito-shop/pkg/logger"
+	"avito-shop/pkg/metrics"
+	"avito-shop/pkg/postgres"
+	"avito-shop/pkg/tracing"
 )
 
 type UseCase struct {
 	repoBalance     BalanceRepo
 	repoTransaction TransactionRepo
-	trManager       *manager.Manager
+	repoLedger      LedgerRepo
+	repoLot         LotRepo
+	repoPending     PendingTransferRepo
+	trManager       trm.Manager
+	policy          entity.SendPolicy
 }
 
-func New(rb *repository.BalanceRepo, rt *repository.TransactionRepo, trManager *manager.Manager) *UseCase {
+func New(rb *repository.BalanceRepo,
+	rt *repository.TransactionRepo,
+	rl *repository.LedgerRepo,
+	rlot *repository.LotRepo,
+	rp *repository.PendingTransferRepo,
+	trManager trm.Manager,
+	policy entity.SendPolicy,
+) *UseCase {
 	return &UseCase{
 		repoBalance:     rb,
 		repoTransaction: rt,
+		repoLedger:      rl,
+		repoLot:         rlot,
+		repoPending:     rp,
 		trManager:       trManager,
+		policy:          policy,
 	}
 }
 
 //go:generate mockery --name=Send
+//go:generate mockery --name=Approval
 
 type (
 	Send interface {
-		SendCoin(ctx context.Context, fromUser, toUser string, amount int) error
+		SendCoin(ctx context.Context, fromUser, toUser string, amount int, message string) (*entity.PendingTransfer, error)
+		SendCoinBatch(ctx context.Context, fromUser string, transfers []entity.Transfer) error
+	}
+
+	Approval interface {
+		ListPending(ctx context.Context) ([]entity.PendingTransfer, error)
+		Approve(ctx context.Context, admin string, id int) (*entity.PendingTransfer, error)
+		Reject(ctx context.Context, admin string, id int) (*entity.PendingTransfer, error)
 	}
 
 	BalanceRepo interface {
-		GetUserBalance(ctx context.Context, username string) (int, error)
 		DecreaseBalance(ctx context.Context, username string, amount int) error
 		IncreaseBalance(ctx context.Context, username string, amount int) error
+		LockBalances(ctx context.Context, usernames ...string) (map[string]int, error)
 	}
 
 	TransactionRepo interface {
 		AddTransaction(ctx context.Context, txn entity.CoinTransaction) error
+		SentSince(ctx context.Context, username string, since time.Time) (int, error)
+	}
+
+	LedgerRepo interface {
+		Record(ctx context.Context, entry entity.LedgerEntry) error
+	}
+
+	LotRepo interface {
+		AddLot(ctx context.Context, username, source string, amount int) error
+		ConsumeLots(ctx context.Context, username string, amount int) error
+	}
+
+	PendingTransferRepo interface {
+		Add(ctx context.Context, pt entity.PendingTransfer) (*entity.PendingTransfer, error)
+		GetForUpdate(ctx context.Context, id int) (*entity.PendingTransfer, error)
+		ListPending(ctx context.Context) ([]entity.PendingTransfer, error)
+		Resolve(ctx context.Context, id int, status, resolvedBy string, resolvedAt time.Time) error
+		PendingSince(ctx context.Context, username string, since time.Time) (int, error)
 	}
 )
 
-func (uc *UseCase) SendCoin(ctx context.Context, fromUser, toUser string, amount int) error {
+// SendCoin moves coins between users. A transfer above the approval threshold
+// is stored as pending and returned instead; it counts towards the limits but
+// no coins move until an admin approves it.
+func (uc *UseCase) SendCoin(ctx context.Context,
+	fromUser, toUser string,
+	amount int,
+	message string,
+) (*entity.PendingTransfer, error) {
 	const op = "usecase.SendCoin"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	if amount <= 0 {
-		return fmt.Errorf("%s: %w", op, errors.ErrInvalidCredentials)
+		return nil, fmt.Errorf("%s: %w", op, errors.ErrInvalidCredentials)
 	}
 
+	var pending *entity.PendingTransfer
+
 	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
-		balance, err := uc.repoBalance.GetUserBalance(ctx, fromUser)
+		pending = nil
+
+		balances, err := uc.repoBalance.LockBalances(ctx, fromUser, toUser)
 		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		_, err = uc.repoBalance.GetUserBalance(ctx, toUser)
-		if err != nil {
+		if balances[fromUser] < amount {
+			return fmt.Errorf("%s: %w", op, errors.ErrInsufficientFunds)
+		}
+
+		if err = uc.checkLimits(ctx, fromUser, amount, time.Now()); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		if balance < amount {
-			return fmt.Errorf("%s: %w", op, errors.ErrInvalidCredentials)
+		if uc.needsApproval(amount) {
+			pending, err = uc.repoPending.Add(ctx, entity.PendingTransfer{
+				FromUser: fromUser,
+				ToUser:   toUser,
+				Amount:   amount,
+				Message:  sanitizeMessage(message),
+				Status:   entity.PendingTransferPending,
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			return nil
 		}
 
-		if err = uc.repoBalance.DecreaseBalance(ctx, fromUser, amount); err != nil {
+		if err = uc.transfer(ctx, fromUser, toUser, amount, message); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		if err = uc.repoBalance.IncreaseBalance(ctx, toUser, amount); err != nil {
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log := logger.FromContext(ctx, slog.Default()).With(
+		slog.String("from", fromUser),
+		slog.String("to", toUser),
+		slog.Int("amount", amount),
+	)
+
+	if pending != nil {
+		log.Info("transfer held for approval")
+
+		return pending, nil
+	}
+
+	// Callers may nest the transfer in their own transaction, so count the
+	// coins only once that commits.
+	postgres.AfterCommit(ctx, func() { metrics.CoinsSent(amount) })
+	log.Info("coins sent")
+
+	return pending, nil
+}
+
+// SendCoinBatch applies all transfers in one transaction: either every
+// recipient gets the coins or nobody does. Transfers that need approval must
+// be sent one by one.
+func (uc *UseCase) SendCoinBatch(ctx context.Context, fromUser string, transfers []entity.Transfer) error {
+	const op = "usecase.SendCoinBatch"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if len(transfers) == 0 {
+		return fmt.Errorf("%s: %w", op, errors.ErrInvalidCredentials)
+	}
+
+	total := 0
+
+	for _, t := range transfers {
+		if t.Amount <= 0 {
+			return fmt.Errorf("%s: %w", op, errors.ErrInvalidCredentials)
+		}
+
+		if uc.needsApproval(t.Amount) {
+			return fmt.Errorf("%s: %w", op, errors.ErrApprovalRequired)
+		}
+
+		total += t.Amount
+	}
+
+	usernames := make([]string, 0, len(transfers)+1)
+	usernames = append(usernames, fromUser)
+
+	for _, t := range transfers {
+		usernames = append(usernames, t.ToUser)
+	}
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		balances, err := uc.repoBalance.LockBalances(ctx, usernames...)
+		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		txn := entity.CoinTransaction{
-			FromUser: fromUser,
-			ToUser:   toUser,
-			Amount:   amount,
+		if balances[fromUser] < total {
+			return fmt.Errorf("%s: %w", op, errors.ErrInsufficientFunds)
 		}
 
-		if err = uc.repoTransaction.AddTransaction(ctx, txn); err != nil {
+		if err = uc.checkLimits(ctx, fromUser, total, time.Now()); err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
+		for _, t := range transfers {
+			if err = uc.transfer(ctx, fromUser, t.ToUser, t.Amount, ""); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
 		return nil
 	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	postgres.AfterCommit(ctx, func() { metrics.CoinsSent(total) })
+	logger.FromContext(ctx, slog.Default()).Info("coins sent in batch",
+		slog.String("from", fromUser),
+		slog.Int("recipients", len(transfers)),
+		slog.Int("amount", total),
+	)
+
+	return nil
+}
+
+func (uc *UseCase) ListPending(ctx context.Context) ([]entity.PendingTransfer, error) {
+	const op = "usecase.send.ListPending"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	transfers, err := uc.repoPending.ListPending(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return transfers, nil
+}
+
+// Approve moves the coins of a pending transfer. The sender's balance is
+// checked again since it may have changed while the transfer was waiting.
+func (uc *UseCase) Approve(ctx context.Context, admin string, id int) (*entity.PendingTransfer, error) {
+	const op = "usecase.send.Approve"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	pt, err := uc.resolve(ctx, op, admin, id, entity.PendingTransferApproved, func(ctx context.Context, pt *entity.PendingTransfer) error {
+		balances, err := uc.repoBalance.LockBalances(ctx, pt.FromUser, pt.ToUser)
+		if err != nil {
+			return err
+		}
+
+		if balances[pt.FromUser] < pt.Amount {
+			return errors.ErrInsufficientFunds
+		}
+
+		return uc.transfer(ctx, pt.FromUser, pt.ToUser, pt.Amount, pt.Message)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	postgres.AfterCommit(ctx, func() { metrics.CoinsSent(pt.Amount) })
+	logger.FromContext(ctx, slog.Default()).Info("pending transfer approved",
+		slog.Int("id", pt.ID),
+		slog.String("admin", admin),
+		slog.String("from", pt.FromUser),
+		slog.String("to", pt.ToUser),
+		slog.Int("amount", pt.Amount),
+	)
+
+	return pt, nil
+}
+
+func (uc *UseCase) Reject(ctx context.Context, admin string, id int) (*entity.PendingTransfer, error) {
+	const op = "usecase.send.Reject"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return uc.resolve(ctx, op, admin, id, entity.PendingTransferRejected, nil)
+}
+
+func (uc *UseCase) resolve(ctx context.Context,
+	op, admin string,
+	id int,
+	status string,
+	action func(ctx context.Context, pt *entity.PendingTransfer) error,
+) (*entity.PendingTransfer, error) {
+	var pt *entity.PendingTransfer
+
+	err := uc.trManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+
+		pt, err = uc.repoPending.GetForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if pt.Status != entity.PendingTransferPending {
+			return fmt.Errorf("%s: %w", op, errors.ErrAlreadyResolved)
+		}
+
+		if action != nil {
+			if err = action(ctx, pt); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		now := time.Now()
+
+		if err = uc.repoPending.Resolve(ctx, id, status, admin, now); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		pt.Status = status
+		pt.ResolvedAt = &now
+		pt.ResolvedBy = admin
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pt, nil
+}
+
+// transfer moves coins between balances that the caller has already locked
+// and checked.
+func (uc *UseCase) transfer(ctx context.Context, fromUser, toUser string, amount int, message string) error {
+	if err := uc.repoBalance.DecreaseBalance(ctx, fromUser, amount); err != nil {
+		return err
+	}
+
+	if err := uc.repoBalance.IncreaseBalance(ctx, toUser, amount); err != nil {
+		return err
+	}
+
+	if err := uc.moveLots(ctx, fromUser, toUser, amount); err != nil {
+		return err
+	}
+
+	txn := entity.CoinTransaction{
+		FromUser: fromUser,
+		ToUser:   toUser,
+		Amount:   amount,
+		Message:  sanitizeMessage(message),
+	}
+
+	if err := uc.repoTransaction.AddTransaction(ctx, txn); err != nil {
+		return err
+	}
+
+	return uc.repoLedger.Record(ctx, entity.TransferEntry(fromUser, toUser, amount))
+}
+
+// checkLimits counts sent and pending coins in the current UTC day and month.
+// The sender's balance row must be locked so that concurrent sends are
+// counted one after another.
+func (uc *UseCase) checkLimits(ctx context.Context, username string, amount int, now time.Time) error {
+	now = now.UTC()
+
+	limits := []struct {
+		limit int
+		since time.Time
+	}{
+		{uc.policy.DailyLimit, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)},
+		{uc.policy.MonthlyLimit, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, l := range limits {
+		if l.limit <= 0 {
+			continue
+		}
+
+		sent, err := uc.repoTransaction.SentSince(ctx, username, l.since)
+		if err != nil {
+			return err
+		}
+
+		pending, err := uc.repoPending.PendingSince(ctx, username, l.since)
+		if err != nil {
+			return err
+		}
+
+		if sent+pending+amount > l.limit {
+			return errors.ErrLimitExceeded
+		}
+	}
+
 	return nil
 }
+
+func (uc *UseCase) needsApproval(amount int) bool {
+	return uc.policy.ApprovalThreshold > 0 && amount > uc.policy.ApprovalThreshold
+}
+
+// moveLots spends the sender's oldest coins; the recipient gets a fresh lot
+// with its own expiry.
+func (uc *UseCase) moveLots(ctx context.Context, fromUser, toUser string, amount int) error {
+	if err := uc.repoLot.ConsumeLots(ctx, fromUser, amount); err != nil {
+		return err
+	}
+
+	return uc.repoLot.AddLot(ctx, toUser, entity.LotSourceTransfer, amount)
+}
+
+// sanitizeMessage drops control characters and surrounding whitespace so the
+// message is safe to show in history as plain text.
+func sanitizeMessage(message string) string {
+	message = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+
+		return r
+	}, message)
+
+	return strings.TrimSpace(message)
+}
@@ -0,0 +1,103 @@
+//go:build integration
+
+package send
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/repository"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/postgres"
+)
+
+// TestSendCoin_ConcurrentOppositeTransfers hammers two users with transfers in
+// both directions. Without ordered row locks A->B and B->A deadlock, and
+// without FOR UPDATE the balance check races and coins are overspent.
+func TestSendCoin_ConcurrentOppositeTransfers(t *testing.T) {
+	url, ok := os.LookupEnv("PG_URL")
+	if !ok {
+		t.Skip("PG_URL is not set")
+	}
+
+	const (
+		initialCoins = 100
+		workers      = 16
+		iterations   = 50
+	)
+
+	pg, err := postgres.New(url, postgres.MaxPoolSize(workers))
+	require.NoError(t, err)
+	t.Cleanup(pg.Close)
+
+	ctx := context.Background()
+	balanceRepo := repository.NewBalanceRepo(pg)
+
+	suffix := time.Now().UnixNano()
+	alice := fmt.Sprintf("stress-alice-%d", suffix)
+	bob := fmt.Sprintf("stress-bob-%d", suffix)
+
+	require.NoError(t, balanceRepo.InitBalance(ctx, alice, initialCoins))
+	require.NoError(t, balanceRepo.InitBalance(ctx, bob, initialCoins))
+
+	uc := New(balanceRepo,
+		repository.NewTransactionRepo(pg),
+		repository.NewLedgerRepo(pg),
+		repository.NewLotRepo(pg),
+		repository.NewPendingTransferRepo(pg),
+		postgres.NewRetryManager(manager.Must(trmpgx.NewDefaultFactory(pg.Pool))),
+		entity.SendPolicy{},
+	)
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, workers*iterations)
+
+	for w := 0; w < workers; w++ {
+		from, to := alice, bob
+		if w%2 == 1 {
+			from, to = bob, alice
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < iterations; i++ {
+				_, err := uc.SendCoin(ctx, from, to, 7, "")
+				if err != nil && !errors.Is(err, e.ErrInsufficientFunds) {
+					errs <- err
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	aliceCoins, err := balanceRepo.GetUserBalance(ctx, alice)
+	require.NoError(t, err)
+
+	bobCoins, err := balanceRepo.GetUserBalance(ctx, bob)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2*initialCoins, aliceCoins+bobCoins)
+	assert.GreaterOrEqual(t, aliceCoins, 0)
+	assert.GreaterOrEqual(t, bobCoins, 0)
+}
@@ -0,0 +1,47 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Statement is an autogenerated mock type for the Statement type
+type Statement struct {
+	mock.Mock
+}
+
+// Export provides a mock function with given fields: ctx, f, fn
+func (_m *Statement) Export(ctx context.Context, f entity.StatementFilter, fn func(entity.StatementLine) error) error {
+	ret := _m.Called(ctx, f, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.StatementFilter, func(entity.StatementLine) error) error); ok {
+		r0 = rf(ctx, f, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStatement creates a new instance of Statement. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStatement(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Statement {
+	mock := &Statement{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
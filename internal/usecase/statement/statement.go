@@ -0,0 +1,59 @@
+package statement
+
+import (
+	"context"
+	"fmt"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/repository"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
+)
+
+type UseCase struct {
+	repoLedger LedgerRepo
+}
+
+func New(rl *repository.LedgerRepo) *UseCase {
+	return &UseCase{
+		repoLedger: rl,
+	}
+}
+
+//go:generate mockery --name=Statement
+
+type (
+	Statement interface {
+		Export(ctx context.Context, f entity.StatementFilter, fn func(entity.StatementLine) error) error
+	}
+
+	LedgerRepo interface {
+		StreamStatement(ctx context.Context, f entity.StatementFilter, fn func(entity.StatementLine) error) error
+	}
+)
+
+// Export streams the user's coin movements to fn in the order they happened.
+// Returning an error from fn stops the export with that error.
+func (uc *UseCase) Export(ctx context.Context, f entity.StatementFilter, fn func(entity.StatementLine) error) error {
+	const op = "usecase.statement.Export"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return fmt.Errorf("%s: %w", op, e.ErrInvalidRequest)
+	}
+
+	err := uc.repoLedger.StreamStatement(ctx, f, func(line entity.StatementLine) error {
+		if username, ok := entity.AccountUser(line.Counterparty); ok {
+			line.Counterparty = username
+		}
+
+		return fn(line)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	entity "avito-shop/internal/entity"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Stats is an autogenerated mock type for the Stats type
+type Stats struct {
+	mock.Mock
+}
+
+// BalanceDistribution provides a mock function with given fields: ctx
+func (_m *Stats) BalanceDistribution(ctx context.Context) (*entity.BalanceDistribution, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BalanceDistribution")
+	}
+
+	var r0 *entity.BalanceDistribution
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*entity.BalanceDistribution, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *entity.BalanceDistribution); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.BalanceDistribution)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Daily provides a mock function with given fields: ctx, r
+func (_m *Stats) Daily(ctx context.Context, r entity.StatsRange) ([]entity.DailyStats, error) {
+	ret := _m.Called(ctx, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Daily")
+	}
+
+	var r0 []entity.DailyStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.StatsRange) ([]entity.DailyStats, error)); ok {
+		return rf(ctx, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.StatsRange) []entity.DailyStats); ok {
+		r0 = rf(ctx, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.DailyStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.StatsRange) error); ok {
+		r1 = rf(ctx, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Economy provides a mock function with given fields: ctx
+func (_m *Stats) Economy(ctx context.Context) (*entity.EconomyStats, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Economy")
+	}
+
+	var r0 *entity.EconomyStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*entity.EconomyStats, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *entity.EconomyStats); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.EconomyStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ItemSales provides a mock function with given fields: ctx, r
+func (_m *Stats) ItemSales(ctx context.Context, r entity.StatsRange) ([]entity.ItemSales, error) {
+	ret := _m.Called(ctx, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ItemSales")
+	}
+
+	var r0 []entity.ItemSales
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.StatsRange) ([]entity.ItemSales, error)); ok {
+		return rf(ctx, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.StatsRange) []entity.ItemSales); ok {
+		r0 = rf(ctx, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ItemSales)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.StatsRange) error); ok {
+		r1 = rf(ctx, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RollUp provides a mock function with given fields: ctx, now
+func (_m *Stats) RollUp(ctx context.Context, now time.Time) (int, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RollUp")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStats creates a new instance of Stats. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStats(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Stats {
+	mock := &Stats{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,198 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+
+	"avito-shop/internal/entity"
+	"avito-shop/internal/repository"
+	e "avito-shop/pkg/errors"
+	"avito-shop/pkg/tracing"
+)
+
+const (
+	// velocityWindow is the trailing period transfer velocity is measured over.
+	velocityWindow = 30 * 24 * time.Hour
+	// maxRangeDays bounds the rollup days returned by one request.
+	maxRangeDays = 366
+)
+
+// balanceBuckets are the lower edges of the balance distribution buckets.
+var balanceBuckets = []int{0, 100, 500, 1000, 2500, 5000, 10000}
+
+type UseCase struct {
+	repoStats StatsRepo
+	trManager trm.Manager
+}
+
+func New(rs *repository.StatsRepo, trManager trm.Manager) *UseCase {
+	return &UseCase{
+		repoStats: rs,
+		trManager: trManager,
+	}
+}
+
+//go:generate mockery --name=Stats
+
+type (
+	Stats interface {
+		Economy(ctx context.Context) (*entity.EconomyStats, error)
+		BalanceDistribution(ctx context.Context) (*entity.BalanceDistribution, error)
+		Daily(ctx context.Context, r entity.StatsRange) ([]entity.DailyStats, error)
+		ItemSales(ctx context.Context, r entity.StatsRange) ([]entity.ItemSales, error)
+		RollUp(ctx context.Context, now time.Time) (int, error)
+	}
+
+	StatsRepo interface {
+		Economy(ctx context.Context, since time.Time) (*entity.EconomyStats, error)
+		BalanceDistribution(ctx context.Context, bounds []int) (*entity.BalanceDistribution, error)
+		LastRollupDay(ctx context.Context) (*time.Time, error)
+		FirstActivityDay(ctx context.Context) (*time.Time, error)
+		RollUpDay(ctx context.Context, day time.Time) error
+		ListDaily(ctx context.Context, r entity.StatsRange) ([]entity.DailyStats, error)
+		ListItemSales(ctx context.Context, r entity.StatsRange) ([]entity.ItemSales, error)
+	}
+)
+
+func (uc *UseCase) Economy(ctx context.Context) (*entity.EconomyStats, error) {
+	const op = "usecase.stats.Economy"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	s, err := uc.repoStats.Economy(ctx, time.Now().Add(-velocityWindow))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.VelocityWindow = velocityWindow.String()
+	if s.Circulation > 0 {
+		s.Velocity = float64(s.Transferred) / float64(s.Circulation)
+	}
+
+	return s, nil
+}
+
+func (uc *UseCase) BalanceDistribution(ctx context.Context) (*entity.BalanceDistribution, error) {
+	const op = "usecase.stats.BalanceDistribution"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	d, err := uc.repoStats.BalanceDistribution(ctx, balanceBuckets)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return d, nil
+}
+
+func (uc *UseCase) Daily(ctx context.Context, r entity.StatsRange) ([]entity.DailyStats, error) {
+	const op = "usecase.stats.Daily"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	r, err := clampRange(r, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	days, err := uc.repoStats.ListDaily(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return days, nil
+}
+
+func (uc *UseCase) ItemSales(ctx context.Context, r entity.StatsRange) ([]entity.ItemSales, error) {
+	const op = "usecase.stats.ItemSales"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	r, err := clampRange(r, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sales, err := uc.repoStats.ListItemSales(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sales, nil
+}
+
+// RollUp materializes every finished UTC day that is not rolled up yet and
+// returns how many days it wrote. The last materialized day is rolled up
+// again, since transactions committing just after midnight are stamped with
+// the time they started.
+func (uc *UseCase) RollUp(ctx context.Context, now time.Time) (int, error) {
+	const op = "usecase.stats.RollUp"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	day, err := uc.repoStats.LastRollupDay(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if day == nil {
+		day, err = uc.repoStats.FirstActivityDay(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if day == nil {
+			return 0, nil
+		}
+	}
+
+	today := startOfDay(now)
+	n := 0
+
+	for d := startOfDay(*day); d.Before(today); d = d.AddDate(0, 0, 1) {
+		err = uc.trManager.Do(ctx, func(ctx context.Context) error {
+			return uc.repoStats.RollUpDay(ctx, d)
+		})
+		if err != nil {
+			return n, fmt.Errorf("%s: %s: %w", op, d.Format(time.DateOnly), err)
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// clampRange defaults the range to the last 30 days and rejects inverted or
+// overly long ranges.
+func clampRange(r entity.StatsRange, now time.Time) (entity.StatsRange, error) {
+	if r.To == nil {
+		to := startOfDay(now)
+		r.To = &to
+	}
+
+	if r.From == nil {
+		from := r.To.AddDate(0, 0, -29)
+		r.From = &from
+	}
+
+	if r.To.Before(*r.From) || r.To.Sub(*r.From) > maxRangeDays*24*time.Hour {
+		return r, e.ErrInvalidRequest
+	}
+
+	return r, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,13 @@
+-- migrations/003_idempotency_keys.up.sql
+
+-- ключи идемпотентности для /api/sendCoin и /api/buy
+
+CREATE TABLE IdempotencyKey (
+    Username VARCHAR(255) NOT NULL,
+    Key VARCHAR(255) NOT NULL,
+    RequestHash VARCHAR(64) NOT NULL,
+    StatusCode INT NOT NULL,
+    Response BYTEA NOT NULL,
+    CreatedAt TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (Username, Key)
+);
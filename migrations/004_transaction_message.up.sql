@@ -0,0 +1,5 @@
+-- migrations/004_transaction_message.up.sql
+
+-- сообщение к переводу монет
+
+ALTER TABLE CoinTransaction ADD COLUMN Message VARCHAR(255) NOT NULL DEFAULT '';
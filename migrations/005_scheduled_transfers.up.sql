@@ -0,0 +1,22 @@
+-- migrations/005_scheduled_transfers.up.sql
+
+-- отложенные и повторяющиеся переводы монет
+
+CREATE TABLE ScheduledTransfer (
+    ID SERIAL PRIMARY KEY,
+    FromUser VARCHAR(255) NOT NULL,
+    ToUser VARCHAR(255) NOT NULL,
+    Amount INT NOT NULL,
+    Message VARCHAR(255) NOT NULL DEFAULT '',
+    Repeat VARCHAR(16) NOT NULL DEFAULT '',
+    NextRunAt TIMESTAMPTZ NOT NULL,
+    -- первый запуск: от него отсчитываются ежемесячные повторы
+    StartsAt TIMESTAMPTZ NOT NULL,
+    Active BOOLEAN NOT NULL DEFAULT TRUE,
+    LastRunAt TIMESTAMPTZ,
+    LastError TEXT NOT NULL DEFAULT '',
+    Failures INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX ScheduledTransfer_Due_Idx ON ScheduledTransfer (NextRunAt) WHERE Active;
+CREATE INDEX ScheduledTransfer_FromUser_Idx ON ScheduledTransfer (FromUser);
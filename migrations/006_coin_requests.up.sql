@@ -0,0 +1,18 @@
+-- migrations/006_coin_requests.up.sql
+
+-- запросы монет у коллег
+
+CREATE TABLE CoinRequest (
+    ID SERIAL PRIMARY KEY,
+    Requester VARCHAR(255) NOT NULL,
+    Payer VARCHAR(255) NOT NULL,
+    Amount INT NOT NULL,
+    Note VARCHAR(255) NOT NULL DEFAULT '',
+    Status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    CreatedAt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ExpiresAt TIMESTAMPTZ NOT NULL,
+    ResolvedAt TIMESTAMPTZ
+);
+
+CREATE INDEX CoinRequest_Payer_Idx ON CoinRequest (Payer, Status);
+CREATE INDEX CoinRequest_Requester_Idx ON CoinRequest (Requester, Status);
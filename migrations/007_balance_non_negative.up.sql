@@ -0,0 +1,5 @@
+-- migrations/007_balance_non_negative.up.sql
+
+-- баланс не может быть отрицательным
+
+ALTER TABLE Balance ADD CONSTRAINT Balance_Coins_Non_Negative CHECK (Coins >= 0);
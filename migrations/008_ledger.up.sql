@@ -0,0 +1,47 @@
+-- migrations/008_ledger.up.sql
+
+-- двойная запись: каждая операция с монетами — сбалансированный набор проводок
+
+CREATE TABLE LedgerEntry (
+    ID SERIAL PRIMARY KEY,
+    Kind VARCHAR(32) NOT NULL,
+    Reference VARCHAR(255) NOT NULL DEFAULT '',
+    CreatedAt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE LedgerPosting (
+    ID SERIAL PRIMARY KEY,
+    EntryID INT NOT NULL REFERENCES LedgerEntry (ID),
+    Account TEXT NOT NULL,
+    Amount INT NOT NULL
+);
+
+CREATE INDEX LedgerPosting_EntryID_Idx ON LedgerPosting (EntryID);
+CREATE INDEX LedgerPosting_Account_Idx ON LedgerPosting (Account);
+
+-- текущие балансы переносятся в журнал как начальные остатки из эмиссии
+WITH entries AS (
+    INSERT INTO LedgerEntry (Kind, Reference)
+    SELECT 'opening', Username FROM Balance WHERE Coins <> 0
+    RETURNING ID, Reference
+)
+INSERT INTO LedgerPosting (EntryID, Account, Amount)
+SELECT e.ID, 'user:' || b.Username, b.Coins FROM entries e JOIN Balance b ON b.Username = e.Reference
+UNION ALL
+SELECT e.ID, 'system:mint', -b.Coins FROM entries e JOIN Balance b ON b.Username = e.Reference;
+
+-- сумма проводок каждой записи должна быть нулевой к моменту коммита
+CREATE FUNCTION ledger_entry_balanced() RETURNS trigger AS $$
+BEGIN
+    IF (SELECT COALESCE(SUM(Amount), 0) FROM LedgerPosting WHERE EntryID = NEW.EntryID) <> 0 THEN
+        RAISE EXCEPTION 'ledger entry % is not balanced', NEW.EntryID;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER LedgerPosting_Balanced
+    AFTER INSERT OR UPDATE ON LedgerPosting
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_entry_balanced();
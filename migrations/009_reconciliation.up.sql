@@ -0,0 +1,41 @@
+-- migrations/009_reconciliation.up.sql
+
+-- отчёты сверки балансов с историей операций
+
+CREATE TABLE ReconciliationRun (
+    ID SERIAL PRIMARY KEY,
+    CheckedAt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    Users INT NOT NULL,
+    Drifted INT NOT NULL,
+    TotalDrift INT NOT NULL
+);
+
+CREATE TABLE BalanceDrift (
+    RunID INT NOT NULL REFERENCES ReconciliationRun (ID) ON DELETE CASCADE,
+    Username VARCHAR(255) NOT NULL,
+    Expected INT NOT NULL,
+    Actual INT NOT NULL,
+    PRIMARY KEY (RunID, Username)
+);
+
+-- покупки до появления журнала не имеют проводок: их сумма фиксируется по ценам
+-- на момент миграции, чтобы сверка не зависела от текущих цен
+CREATE TABLE LegacyPurchase (
+    Username VARCHAR(255) PRIMARY KEY,
+    Spent INT NOT NULL
+);
+
+WITH InventorySpent AS (
+    SELECT i.Username, SUM(i.Quantity * it.Price) AS Spent
+    FROM Inventory i JOIN Item it ON it.Name = i.Item
+    GROUP BY i.Username
+), LedgerSpent AS (
+    SELECT p.Account, -SUM(p.Amount) AS Spent
+    FROM LedgerPosting p JOIN LedgerEntry e ON e.ID = p.EntryID
+    WHERE e.Kind = 'purchase'
+    GROUP BY p.Account
+)
+INSERT INTO LegacyPurchase (Username, Spent)
+SELECT s.Username, s.Spent - COALESCE(l.Spent, 0)
+FROM InventorySpent s LEFT JOIN LedgerSpent l ON l.Account = 'user:' || s.Username
+WHERE s.Spent <> COALESCE(l.Spent, 0);
@@ -0,0 +1,21 @@
+-- migrations/010_coin_grants.up.sql
+
+-- начисления и списания монет администраторами, включая стартовый бонус
+
+CREATE TABLE CoinGrant (
+    ID SERIAL PRIMARY KEY,
+    Username VARCHAR(255) NOT NULL,
+    Amount INT NOT NULL CHECK (Amount <> 0),
+    Reason VARCHAR(255) NOT NULL,
+    Author VARCHAR(255) NOT NULL,
+    CreatedAt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX CoinGrant_Username_Idx ON CoinGrant (Username);
+
+-- стартовый бонус существующих пользователей был зашит в код
+INSERT INTO CoinGrant (Username, Amount, Reason, Author)
+SELECT Username, 1000, 'onboarding', 'system' FROM Balance;
+
+-- роль администратора выдаётся только командой create-admin, регистрация через /api/auth её не даёт
+ALTER TABLE Users ADD COLUMN IsAdmin BOOLEAN NOT NULL DEFAULT FALSE;
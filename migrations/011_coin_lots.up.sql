@@ -0,0 +1,28 @@
+-- migrations/011_coin_lots.up.sql
+
+-- партии монет для сгорания через 12 месяцев после получения
+
+CREATE TABLE CoinLot (
+    ID SERIAL PRIMARY KEY,
+    Username VARCHAR(255) NOT NULL,
+    Source VARCHAR(32) NOT NULL,
+    Amount INT NOT NULL CHECK (Amount > 0),
+    Remaining INT NOT NULL CHECK (Remaining >= 0 AND Remaining <= Amount),
+    ReceivedAt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX CoinLot_Username_ReceivedAt_Idx ON CoinLot (Username, ReceivedAt) WHERE Remaining > 0;
+
+CREATE TABLE CoinExpiry (
+    ID SERIAL PRIMARY KEY,
+    LotID INT NOT NULL REFERENCES CoinLot (ID),
+    Username VARCHAR(255) NOT NULL,
+    Amount INT NOT NULL,
+    ExpiredAt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX CoinExpiry_Username_Idx ON CoinExpiry (Username);
+
+-- текущие остатки становятся одной партией, срок отсчитывается с момента миграции
+INSERT INTO CoinLot (Username, Source, Amount, Remaining)
+SELECT Username, 'opening', Coins, Coins FROM Balance WHERE Coins > 0;
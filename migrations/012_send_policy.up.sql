@@ -0,0 +1,25 @@
+-- migrations/012_send_policy.up.sql
+
+-- время перевода нужно для дневных и месячных лимитов; у старых записей оно неизвестно
+-- и остаётся NULL, чтобы они не попадали ни в одно окно по времени
+ALTER TABLE CoinTransaction ADD COLUMN CreatedAt TIMESTAMPTZ;
+ALTER TABLE CoinTransaction ALTER COLUMN CreatedAt SET DEFAULT NOW();
+
+CREATE INDEX CoinTransaction_FromUser_CreatedAt_Idx ON CoinTransaction (FromUser, CreatedAt);
+
+-- крупные переводы ждут решения администратора
+
+CREATE TABLE PendingTransfer (
+    ID SERIAL PRIMARY KEY,
+    FromUser VARCHAR(255) NOT NULL,
+    ToUser VARCHAR(255) NOT NULL,
+    Amount INT NOT NULL CHECK (Amount > 0),
+    Message VARCHAR(200) NOT NULL DEFAULT '',
+    Status VARCHAR(16) NOT NULL,
+    CreatedAt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ResolvedAt TIMESTAMPTZ,
+    ResolvedBy VARCHAR(255)
+);
+
+CREATE INDEX PendingTransfer_FromUser_Idx ON PendingTransfer (FromUser, CreatedAt);
+CREATE INDEX PendingTransfer_Status_Idx ON PendingTransfer (Status) WHERE Status = 'pending';
@@ -0,0 +1,8 @@
+-- migrations/013_transaction_reversal.up.sql
+
+-- отмена перевода администратором: компенсирующая запись ссылается на исходную
+
+ALTER TABLE CoinTransaction
+    ADD COLUMN ReversalOf INT UNIQUE REFERENCES CoinTransaction (ID),
+    ADD COLUMN ReversedAt TIMESTAMPTZ,
+    ADD COLUMN ReversedBy VARCHAR(255);
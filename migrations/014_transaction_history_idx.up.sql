@@ -0,0 +1,6 @@
+-- migrations/014_transaction_history_idx.up.sql
+
+-- постраничная выдача истории идёт по убыванию ID для каждой стороны перевода
+
+CREATE INDEX CoinTransaction_FromUser_ID_Idx ON CoinTransaction (FromUser, ID);
+CREATE INDEX CoinTransaction_ToUser_ID_Idx ON CoinTransaction (ToUser, ID);
@@ -0,0 +1,9 @@
+-- migrations/015_leaderboard.up.sql
+
+-- пользователь может скрыть себя из рейтингов
+
+ALTER TABLE Users ADD COLUMN LeaderboardOptOut BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- рейтинги считаются за окно по времени создания
+CREATE INDEX CoinTransaction_CreatedAt_Idx ON CoinTransaction (CreatedAt);
+CREATE INDEX LedgerEntry_Kind_CreatedAt_Idx ON LedgerEntry (Kind, CreatedAt);
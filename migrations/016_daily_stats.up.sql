@@ -0,0 +1,26 @@
+-- migrations/016_daily_stats.up.sql
+
+-- ежедневные сводки по экономике монет, дни считаются по UTC
+
+CREATE TABLE DailyCoinStats (
+    Day DATE PRIMARY KEY,
+    Minted INT NOT NULL,
+    ClawedBack INT NOT NULL,
+    Expired INT NOT NULL,
+    SpentInShop INT NOT NULL,
+    Transferred INT NOT NULL,
+    Transfers INT NOT NULL,
+    Circulation INT NOT NULL,
+    RolledUpAt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE DailyItemSales (
+    Day DATE NOT NULL,
+    Item VARCHAR(255) NOT NULL,
+    Sold INT NOT NULL,
+    Coins INT NOT NULL,
+    PRIMARY KEY (Day, Item)
+);
+
+CREATE INDEX CoinGrant_CreatedAt_Idx ON CoinGrant (CreatedAt);
+CREATE INDEX CoinExpiry_ExpiredAt_Idx ON CoinExpiry (ExpiredAt);
@@ -3,9 +3,21 @@ package errors
 import "errors"
 
 var (
-	ErrUserNotFound       = errors.New("user not found")
-	ErrInvalidUsername    = errors.New("invalid username")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrNotFound           = errors.New("not found")
-	ErrMultiplyRows       = errors.New("multiple rows returned")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrInvalidUsername      = errors.New("invalid username")
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrInsufficientFunds    = errors.New("insufficient funds")
+	ErrNotFound             = errors.New("not found")
+	ErrInvalidRequest       = errors.New("invalid request")
+	ErrUnbalancedEntry      = errors.New("ledger entry is not balanced")
+	ErrMultiplyRows         = errors.New("multiple rows returned")
+	ErrAlreadyResolved      = errors.New("already resolved")
+	ErrExpired              = errors.New("expired")
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused with different request")
+	ErrLimitExceeded        = errors.New("send limit exceeded")
+	ErrApprovalRequired     = errors.New("transfer requires approval")
+	ErrPoolSaturated        = errors.New("worker pool is saturated")
+	ErrPoolClosed           = errors.New("worker pool is closed")
+	ErrTaskPanicked         = errors.New("task panicked")
+	ErrSchemaMismatch       = errors.New("database schema does not match the expected migration")
 )
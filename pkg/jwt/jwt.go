@@ -1,27 +1,57 @@
 package jwt
 
 import (
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/exp/slog"
+
+	"avito-shop/pkg/logger"
 )
 
-var jwtKey = []byte("my-avito-secret-key")
+// minKeyLength is the shortest signing key accepted: HS256 needs 256 bits.
+const minKeyLength = 32
+
+var errNoKey = errors.New("jwt: signing key is not set")
+
+// jwtKey signs and verifies tokens. It comes from config; until SetKey is
+// called no token is issued or accepted.
+var jwtKey []byte
+
+// SetKey sets the signing key. It must be called before the server starts.
+func SetKey(key string) error {
+	if len(key) < minKeyLength {
+		return fmt.Errorf("jwt - SetKey: key must be at least %d bytes", minKeyLength)
+	}
+
+	jwtKey = []byte(key)
+
+	return nil
+}
 
 type Claims struct {
 	Username string `json:"username"`
+	Admin    bool   `json:"admin,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(username string) (string, error) {
+// GenerateToken signs a token for username. admin comes from the user's role
+// in the database and is what AdminMW checks.
+func GenerateToken(username string, admin bool) (string, error) {
+	if len(jwtKey) == 0 {
+		return "", errNoKey
+	}
+
 	expirationTime := time.Now().Add(24 * time.Hour)
 
 	claims := &Claims{
 		Username: username,
+		Admin:    admin,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -57,10 +87,14 @@ func AuthMW() gin.HandlerFunc {
 		}
 
 		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+			if len(jwtKey) == 0 {
+				return nil, errNoKey
+			}
+
 			return jwtKey, nil
-		})
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 		if err != nil || !token.Valid {
-			log.Printf("Invalid token: %v", err)
+			logger.FromContext(c.Request.Context(), slog.Default()).Warn("Invalid token", slog.Any("error", err))
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 
 			return
@@ -68,6 +102,8 @@ func AuthMW() gin.HandlerFunc {
 
 		if claims, ok := token.Claims.(*Claims); ok {
 			c.Set("username", claims.Username)
+			c.Set("admin", claims.Admin)
+			c.Request = c.Request.WithContext(logger.With(c.Request.Context(), slog.String("username", claims.Username)))
 		} else {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 
@@ -77,3 +113,17 @@ func AuthMW() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// AdminMW allows the request only for tokens issued to admins. It must run
+// after AuthMW.
+func AdminMW() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("admin") {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+
+			return
+		}
+
+		c.Next()
+	}
+}
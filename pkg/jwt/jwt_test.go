@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const testKey = "0123456789abcdef0123456789abcdef"
+
+func TestMain(m *testing.M) {
+	if err := SetKey(testKey); err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func adminRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/admin", AuthMW(), AdminMW(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	return r
+}
+
+func serveAdmin(token string) int {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	adminRouter().ServeHTTP(w, req)
+
+	return w.Code
+}
+
+func TestAdminMW(t *testing.T) {
+	tests := []struct {
+		name  string
+		admin bool
+		want  int
+	}{
+		{"admin", true, http.StatusNoContent},
+		{"regular user", false, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := GenerateToken("root", tt.admin)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.want, serveAdmin(token))
+		})
+	}
+}
+
+func TestAuthMW_ForgedToken(t *testing.T) {
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{Username: "root", Admin: true}).
+		SignedString([]byte("my-avito-secret-key"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, serveAdmin(forged))
+}
+
+func TestSetKey_TooShort(t *testing.T) {
+	assert.Error(t, SetKey("my-avito-secret-key"))
+}
@@ -0,0 +1,111 @@
+// Package lifecycle shuts the service down in a fixed order of stages, each
+// under its own timeout.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"avito-shop/pkg/logger/sl"
+)
+
+// StopFunc stops one part of the service. It should return once ctx is done.
+type StopFunc func(ctx context.Context) error
+
+type stage struct {
+	name    string
+	timeout time.Duration
+	stop    StopFunc
+}
+
+// Manager runs the stages in the order they were added.
+type Manager struct {
+	log    *slog.Logger
+	stages []stage
+
+	once sync.Once
+	err  error
+}
+
+func New(log *slog.Logger) *Manager {
+	return &Manager{log: log}
+}
+
+// Add appends a stage. A timeout of zero or less lets it run to completion.
+func (m *Manager) Add(name string, timeout time.Duration, stop StopFunc) {
+	m.stages = append(m.stages, stage{name, timeout, stop})
+}
+
+// Shutdown runs every stage, even after one fails or times out, and returns
+// their errors joined. Only the first call runs the stages; later calls
+// return its result.
+//
+// A stage that times out is abandoned, not stopped: its StopFunc keeps
+// running in the background while the later stages run, and may still be
+// running when Shutdown returns. A later stage can therefore close something
+// it still uses, such as the Postgres pool under a stuck job. The timeouts
+// bound how long the process takes to exit, they do not make that safe.
+func (m *Manager) Shutdown() error {
+	m.once.Do(func() {
+		var errs []error
+
+		for _, s := range m.stages {
+			if err := m.run(s); err != nil {
+				m.log.Error("shutdown stage failed", slog.String("stage", s.name), sl.Err(err))
+				errs = append(errs, err)
+			}
+		}
+
+		m.err = errors.Join(errs...)
+	})
+
+	return m.err
+}
+
+// run waits for the stage until its timeout. A stop that ignores ctx is left
+// running in the background so the stages after it still get their turn; see
+// Shutdown.
+func (m *Manager) run(s stage) error {
+	ctx := context.Background()
+
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+
+	go func() {
+		done <- s.stop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("lifecycle - %s: %w", s.name, err)
+		}
+
+		m.log.Info("shutdown stage done", slog.String("stage", s.name), slog.Duration("took", time.Since(start)))
+
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("lifecycle - %s: %w", s.name, ctx.Err())
+	}
+}
+
+// Func adapts a blocking stop without a context, such as a pool's Shutdown.
+func Func(stop func()) StopFunc {
+	return func(context.Context) error {
+		stop()
+
+		return nil
+	}
+}
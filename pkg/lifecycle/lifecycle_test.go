@@ -0,0 +1,95 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slog"
+)
+
+func newManager() *Manager {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestManager_Shutdown_Order(t *testing.T) {
+	m := newManager()
+
+	var ran []string
+
+	for _, name := range []string{"http", "workers", "postgres"} {
+		m.Add(name, time.Second, func(context.Context) error {
+			ran = append(ran, name)
+
+			return nil
+		})
+	}
+
+	assert.NoError(t, m.Shutdown())
+	assert.Equal(t, []string{"http", "workers", "postgres"}, ran)
+}
+
+func TestManager_Shutdown_StageTimeout(t *testing.T) {
+	m := newManager()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	m.Add("stuck", 10*time.Millisecond, func(context.Context) error {
+		<-release
+
+		return nil
+	})
+
+	next := false
+
+	m.Add("next", time.Second, func(context.Context) error {
+		next = true
+
+		return nil
+	})
+
+	start := time.Now()
+	err := m.Shutdown()
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "stuck")
+	assert.True(t, next, "a timed out stage does not hold up the next one")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestManager_Shutdown_JoinsErrors(t *testing.T) {
+	m := newManager()
+
+	errHTTP := errors.New("http failed")
+	errPG := errors.New("postgres failed")
+
+	m.Add("http", 0, func(context.Context) error { return errHTTP })
+	m.Add("workers", 0, Func(func() {}))
+	m.Add("postgres", 0, func(context.Context) error { return errPG })
+
+	err := m.Shutdown()
+
+	assert.ErrorIs(t, err, errHTTP)
+	assert.ErrorIs(t, err, errPG)
+}
+
+func TestManager_Shutdown_Once(t *testing.T) {
+	m := newManager()
+
+	calls := 0
+	errStop := errors.New("stop failed")
+
+	m.Add("http", 0, func(context.Context) error {
+		calls++
+
+		return errStop
+	})
+
+	assert.ErrorIs(t, m.Shutdown(), errStop)
+	assert.ErrorIs(t, m.Shutdown(), errStop)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"context"
+
+	"golang.org/x/exp/slog"
+)
+
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx carrying log.
+func WithContext(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, log)
+}
+
+// FromContext returns the logger stored in ctx by WithContext, or fallback.
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if log, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return log
+	}
+
+	return fallback
+}
+
+// With adds attributes to the logger stored in ctx. Without one, ctx is
+// returned as is.
+func With(ctx context.Context, args ...any) context.Context {
+	log, ok := ctx.Value(ctxKey{}).(*slog.Logger)
+	if !ok {
+		return ctx
+	}
+
+	return WithContext(ctx, log.With(args...))
+}
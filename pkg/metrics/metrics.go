@@ -0,0 +1,109 @@
+// Package metrics defines the Prometheus metrics of the service. They are
+// registered in the default registry and served by promhttp.Handler.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "avito_shop"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	coinsSent = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "coins_sent_total",
+		Help:      "Coins moved between users by completed transfers.",
+	})
+
+	itemsBought = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "items_bought_total",
+		Help:      "Merch purchases by item.",
+	}, []string{"item"})
+
+	logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by result: success or failure (wrong password).",
+	}, []string{"result"})
+
+	reconciliationDriftedUsers = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "reconciliation_drifted_users",
+		Help:      "Users whose balance disagreed with the ledger in the last reconciliation.",
+	})
+
+	reconciliationTotalDrift = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "reconciliation_total_drift",
+		Help:      "Sum of the balance drifts found by the last reconciliation.",
+	})
+
+	reconciliationLastRun = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "reconciliation_last_run_timestamp_seconds",
+		Help:      "Unix time of the last completed reconciliation.",
+	})
+)
+
+// HTTP records the count and latency of every request. Requests that match
+// no route are grouped under "unmatched" to keep the label set bounded.
+func HTTP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		status := strconv.Itoa(c.Writer.Status())
+
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+func CoinsSent(amount int) {
+	coinsSent.Add(float64(amount))
+}
+
+func ItemBought(item string) {
+	itemsBought.WithLabelValues(item).Inc()
+}
+
+func Login(success bool) {
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+
+	logins.WithLabelValues(result).Inc()
+}
+
+// Reconciled publishes the outcome of a reconciliation run.
+func Reconciled(driftedUsers, totalDrift int, at time.Time) {
+	reconciliationDriftedUsers.Set(float64(driftedUsers))
+	reconciliationTotalDrift.Set(float64(totalDrift))
+	reconciliationLastRun.Set(float64(at.Unix()))
+}
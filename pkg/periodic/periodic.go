@@ -0,0 +1,83 @@
+// Package periodic runs a background job on a fixed interval.
+package periodic
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Runner calls its job on every tick until it is shut down. Each run gets a
+// context that times out after one interval, so a slow run never overlaps
+// the next one by more than that.
+type Runner struct {
+	interval    time.Duration
+	job         func(ctx context.Context)
+	immediately bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// Option -.
+type Option func(*Runner)
+
+// Immediately runs the job once on Start, before the first tick.
+func Immediately() Option {
+	return func(r *Runner) {
+		r.immediately = true
+	}
+}
+
+func New(interval time.Duration, job func(ctx context.Context), opts ...Option) *Runner {
+	r := &Runner{
+		interval: interval,
+		job:      job,
+		stop:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+func (r *Runner) Start() {
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		if r.immediately {
+			r.run()
+		}
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.run()
+			}
+		}
+	}()
+}
+
+func (r *Runner) run() {
+	ctx, cancel := context.WithTimeout(context.Background(), r.interval)
+	defer cancel()
+
+	r.job(ctx)
+}
+
+// Shutdown stops the ticker and waits for the running job to finish.
+// It is safe to call more than once.
+func (r *Runner) Shutdown() {
+	r.stopOnce.Do(func() { close(r.stop) })
+	r.wg.Wait()
+}
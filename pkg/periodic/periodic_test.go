@@ -0,0 +1,58 @@
+package periodic
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunner_Ticks(t *testing.T) {
+	var runs atomic.Int32
+
+	r := New(5*time.Millisecond, func(context.Context) { runs.Add(1) })
+	r.Start()
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond)
+
+	r.Shutdown()
+	r.Shutdown()
+
+	stopped := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load(), "no runs after Shutdown")
+}
+
+func TestRunner_Immediately(t *testing.T) {
+	ran := make(chan struct{}, 1)
+
+	r := New(time.Hour, func(context.Context) { ran <- struct{}{} }, Immediately())
+	r.Start()
+	defer r.Shutdown()
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("job did not run on Start")
+	}
+}
+
+func TestRunner_ShutdownWaitsForRun(t *testing.T) {
+	started := make(chan struct{})
+
+	var finished atomic.Bool
+
+	r := New(time.Hour, func(context.Context) {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		finished.Store(true)
+	}, Immediately())
+	r.Start()
+
+	<-started
+	r.Shutdown()
+
+	assert.True(t, finished.Load())
+}
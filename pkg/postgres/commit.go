@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+)
+
+type commitHooksKey struct{}
+
+// commitHooks collects the callbacks registered inside one Do call.
+type commitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+func (h *commitHooks) add(fns ...func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.fns = append(h.fns, fns...)
+}
+
+func (h *commitHooks) run() {
+	h.mu.Lock()
+	fns := h.fns
+	h.fns = nil
+	h.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// AfterCommit runs fn once the outermost transaction in ctx commits, or right
+// away when ctx is not inside a RetryManager transaction. Callbacks of an
+// attempt that rolls back, is retried or whose savepoint is rolled back are
+// dropped, so side effects such as metrics count only what was committed.
+func AfterCommit(ctx context.Context, fn func()) {
+	if h, ok := ctx.Value(commitHooksKey{}).(*commitHooks); ok {
+		h.add(fn)
+
+		return
+	}
+
+	fn()
+}
+
+// withCommitHooks gives do its own set of callbacks. On success they are
+// handed to the enclosing Do or, for the outermost one, run since the
+// transaction has committed by then.
+func withCommitHooks(ctx context.Context, do func(ctx context.Context) error) error {
+	parent, _ := ctx.Value(commitHooksKey{}).(*commitHooks)
+	hooks := &commitHooks{}
+
+	if err := do(context.WithValue(ctx, commitHooksKey{}, hooks)); err != nil {
+		return err
+	}
+
+	if parent != nil {
+		parent.add(hooks.fns...)
+
+		return nil
+	}
+
+	hooks.run()
+
+	return nil
+}
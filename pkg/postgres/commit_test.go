@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAfterCommit_OutsideTransaction(t *testing.T) {
+	ran := false
+
+	AfterCommit(context.Background(), func() { ran = true })
+
+	assert.True(t, ran)
+}
+
+func TestAfterCommit_RunsAfterOutermostCommit(t *testing.T) {
+	var ran []string
+
+	err := withCommitHooks(context.Background(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() { ran = append(ran, "outer") })
+
+		err := withCommitHooks(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { ran = append(ran, "nested") })
+
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, ran, "nothing runs before the outermost commit")
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"outer", "nested"}, ran)
+}
+
+func TestAfterCommit_DropsRolledBack(t *testing.T) {
+	errRollback := errors.New("rollback")
+
+	t.Run("savepoint", func(t *testing.T) {
+		var ran []string
+
+		err := withCommitHooks(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func() { ran = append(ran, "outer") })
+
+			_ = withCommitHooks(ctx, func(ctx context.Context) error {
+				AfterCommit(ctx, func() { ran = append(ran, "nested") })
+
+				return errRollback
+			})
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"outer"}, ran)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		ran := false
+
+		err := withCommitHooks(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func() { ran = true })
+
+			return errRollback
+		})
+
+		assert.ErrorIs(t, err, errRollback)
+		assert.False(t, ran)
+	})
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	trmcontext "github.com/avito-tech/go-transaction-manager/trm/v2/context"
+	"github.com/jackc/pgconn"
+)
+
+const (
+	_defaultRetryAttempts = 5
+	_defaultRetryBackoff  = 10 * time.Millisecond
+
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+// RetryManager re-runs a whole transaction when Postgres aborts it with a
+// deadlock or serialization failure. Nested calls are not retried on their
+// own: the outermost transaction is already aborted and is retried instead.
+type RetryManager struct {
+	trm.Manager
+	attempts int
+	backoff  time.Duration
+}
+
+func NewRetryManager(m trm.Manager) *RetryManager {
+	return &RetryManager{
+		Manager:  m,
+		attempts: _defaultRetryAttempts,
+		backoff:  _defaultRetryBackoff,
+	}
+}
+
+func (m *RetryManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.retry(ctx, func(ctx context.Context) error {
+		return m.Manager.Do(ctx, fn)
+	})
+}
+
+func (m *RetryManager) DoWithSettings(ctx context.Context, s trm.Settings, fn func(ctx context.Context) error) error {
+	return m.retry(ctx, func(ctx context.Context) error {
+		return m.Manager.DoWithSettings(ctx, s, fn)
+	})
+}
+
+// retry also scopes the AfterCommit callbacks to each attempt.
+func (m *RetryManager) retry(ctx context.Context, do func(ctx context.Context) error) error {
+	if trmcontext.DefaultManager.Default(ctx) != nil {
+		return withCommitHooks(ctx, do)
+	}
+
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		err = withCommitHooks(ctx, do)
+		if err == nil || !IsRetryable(err) || attempt == m.attempts {
+			return err
+		}
+
+		// Jitter keeps two transactions that deadlocked from colliding again.
+		delay := time.Duration(attempt)*m.backoff + time.Duration(rand.Int63n(int64(m.backoff)))
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// IsRetryable reports whether err is a transient conflict between concurrent
+// transactions.
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == codeSerializationFailure || pgErr.Code == codeDeadlockDetected
+}
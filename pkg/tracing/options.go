@@ -0,0 +1,27 @@
+package tracing
+
+// Option -.
+type Option func(*Tracing)
+
+// Exporter selects where spans go: "otlp", "stdout" or "none".
+func Exporter(exporter string) Option {
+	return func(t *Tracing) {
+		t.exporter = exporter
+	}
+}
+
+// Endpoint is the OTLP/HTTP collector URL. When empty the exporter falls back
+// to the OTEL_EXPORTER_OTLP_* environment variables and then to localhost.
+func Endpoint(url string) Option {
+	return func(t *Tracing) {
+		t.endpoint = url
+	}
+}
+
+// SampleRatio is the share of new traces recorded; requests that arrive with
+// a sampled traceparent are always recorded.
+func SampleRatio(ratio float64) Option {
+	return func(t *Tracing) {
+		t.sampleRatio = ratio
+	}
+}
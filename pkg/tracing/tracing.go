@@ -0,0 +1,137 @@
+// Package tracing sets up OpenTelemetry tracing: a tracer provider exporting
+// over OTLP/HTTP or to stdout and the W3C trace context propagator.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+
+	tracerName = "avito-shop"
+
+	_defaultSampleRatio = 1.0
+)
+
+// Tracing owns the tracer provider installed as the global one.
+type Tracing struct {
+	exporter    string
+	endpoint    string
+	sampleRatio float64
+
+	provider *sdktrace.TracerProvider
+}
+
+// New installs the W3C trace context propagator and, unless the exporter is
+// "none", a tracer provider. Without one the global provider stays a no-op,
+// so spans cost next to nothing.
+func New(ctx context.Context, service, version string, opts ...Option) (*Tracing, error) {
+	t := &Tracing{
+		exporter:    ExporterNone,
+		sampleRatio: _defaultSampleRatio,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch t.exporter {
+	case ExporterNone, "":
+		return t, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		var httpOpts []otlptracehttp.Option
+		if t.endpoint != "" {
+			httpOpts = append(httpOpts, otlptracehttp.WithEndpointURL(t.endpoint))
+		}
+
+		exporter, err = otlptracehttp.New(ctx, httpOpts...)
+	default:
+		return nil, fmt.Errorf("tracing - New: unknown exporter %q", t.exporter)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("tracing - New - %s exporter: %w", t.exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(service),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("tracing - New - resource.Merge: %w", err)
+	}
+
+	t.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(t.sampleRatio))),
+	)
+
+	otel.SetTracerProvider(t.provider)
+
+	return t, nil
+}
+
+// Shutdown flushes the spans still buffered and stops the exporter.
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+
+	return t.provider.Shutdown(ctx)
+}
+
+// Start opens a span named after the caller's op constant.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// StartQuery opens a client span for a repository method; name is its op
+// constant, which identifies the SQL statement.
+func StartQuery(ctx context.Context, name string) (context.Context, trace.Span) {
+	return Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			attribute.String("db.operation.name", name),
+		),
+	)
+}
+
+// RecordError marks the span as failed with err; a nil err is ignored.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}